@@ -1,83 +1,588 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 	"github.com/library-api/internal/api"
-	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/auth"
+	"github.com/library-api/internal/config"
+	"github.com/library-api/internal/logging"
+	"github.com/library-api/internal/metrics"
+	"github.com/library-api/internal/migrations"
+	"github.com/library-api/internal/problem"
 	"github.com/library-api/internal/repository"
 	"github.com/library-api/internal/service"
+	"github.com/library-api/internal/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// @title Library API
+// @version 1.0
+// @description Catalog, membership and circulation API for a lending library. Every /api/v1 route takes a bearer token or an API key.
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description A JWT sent as "Bearer <token>"
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
+	// `api migrate ...` manages the schema and exits, so it does not need the
+	// server's settings
+	migrating := len(os.Args) > 1 && os.Args[1] == "migrate"
+	load := config.Load
+	if migrating {
+		load = config.LoadForMigrate
+	}
+	cfg, err := load()
+	if err != nil {
+		fatal(slog.Default(), "invalid configuration", err)
 	}
 
-	// Database connection
-	dsn := os.Getenv("DATABASE_URL")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// Logging; anything still written through the log package ends up here too
+	logger := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	slog.SetDefault(logger)
+
+	// Tracing; spans are dropped, and traces is nil, unless enabled
+	tracerProvider, traces, err := newTracerProvider(cfg.Tracing, logger)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		fatal(logger, "failed to set up tracing", err)
 	}
 
-	// Auto-migrate database tables
-	err = db.AutoMigrate(&models.Author{}, &models.Publisher{}, &models.Book{})
+	// Database connection
+	db, err := openDB(cfg.Database, logger, tracerProvider)
 	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		fatal(logger, "failed to connect to database", err)
 	}
 
-	// Add a CHECK constraint for quantity_issued validity
-	if !db.Migrator().HasConstraint(&models.Book{}, "chk_quantity_issued_valid") {
-		err = db.Exec(`
-		ALTER TABLE books 
-		ADD CONSTRAINT chk_quantity_issued_valid 
-		CHECK (quantity_issued >= 0 AND quantity_issued <= quantity);
-	`).Error
-		if err != nil {
-			log.Fatalf("Failed to create constraint chk_quantity_issued_valid: %v", err)
+	if migrating {
+		if err := runMigrate(db, logger, os.Args[2:]); err != nil {
+			fatal(logger, "migrate failed", err)
+		}
+		return
+	}
+
+	// Apply pending migrations unless they are run as a separate deploy step
+	if cfg.Database.MigrateOnStart {
+		if err := runMigrate(db, logger, []string{"up"}); err != nil {
+			fatal(logger, "migrate failed", err)
 		}
 	}
 
 	// Initialize repositories
-	bookRepo := repository.NewBookRepository(db)
+	bookRepo := repository.NewBookRepository(db, cfg.Database.QueryTimeout)
+	authorRepo := repository.NewAuthorRepository(db)
+	publisherRepo := repository.NewPublisherRepository(db)
+	memberRepo := repository.NewMemberRepository(db)
+	loanRepo := repository.NewLoanRepository(db)
+	holdRepo := repository.NewHoldRepository(db)
+	fineRepo := repository.NewFineRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+
+	// Circulation policy
+	circulationPolicy := service.CirculationPolicy{
+		LoanPeriod:              time.Duration(cfg.Circulation.LoanPeriodDays) * 24 * time.Hour,
+		MaxRenewals:             cfg.Circulation.MaxRenewals,
+		HoldPickupWindow:        time.Duration(cfg.Circulation.HoldPickupDays) * 24 * time.Hour,
+		FineDailyRateCents:      cfg.Circulation.FineDailyRateCents,
+		FineCapCents:            cfg.Circulation.FineCapCents,
+		FineBlockThresholdCents: cfg.Circulation.FineBlockThresholdCents,
+	}
+
+	// Metrics
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal(logger, "failed to access connection pool", err)
+	}
+	registry := metrics.NewRegistry()
+	httpMetrics := metrics.NewHTTP(registry)
+	metrics.RegisterDBStats(registry, sqlDB)
+	circulationMetrics := metrics.NewCirculation(registry, logger, func() (int64, error) {
+		return bookRepo.CountIssuedCopies(context.Background())
+	})
 
 	// Initialize services
-	bookService := service.NewBookService(bookRepo)
+	bookService := service.NewTracedBookService(
+		service.NewBookService(bookRepo, circulationPolicy, circulationMetrics, logger), tracerProvider)
+	authorService := service.NewAuthorService(authorRepo)
+	publisherService := service.NewPublisherService(publisherRepo)
+	memberService := service.NewMemberService(memberRepo)
+	loanService := service.NewLoanService(loanRepo, bookRepo, memberRepo)
+	holdService := service.NewHoldService(holdRepo, bookRepo, memberRepo, circulationPolicy, logger)
+	fineService := service.NewFineService(fineRepo, memberRepo, circulationPolicy, logger)
+	searchService := service.NewSearchService(searchRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, logger)
 
 	// Initialize handlers
-	bookHandler := api.NewBookHandler(bookService)
+	h := handlers{
+		book:      api.NewBookHandler(bookService),
+		author:    api.NewAuthorHandler(authorService),
+		publisher: api.NewPublisherHandler(publisherService),
+		member:    api.NewMemberHandler(memberService),
+		loan:      api.NewLoanHandler(loanService),
+		hold:      api.NewHoldHandler(holdService, memberService),
+		fine:      api.NewFineHandler(fineService),
+		search:    api.NewSearchHandler(searchService),
+		apiKey:    api.NewAPIKeyHandler(apiKeyService),
+		self:      api.NewSelfHandler(memberService, loanService, holdService, fineService),
+	}
+
+	// SIGINT or SIGTERM starts a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Background jobs
+	var jobs sync.WaitGroup
+	jobs.Add(2)
+	go func() {
+		defer jobs.Done()
+		runPeriodically(ctx, logger, time.Minute, "process holds", func(ctx context.Context) error {
+			_, err := holdService.ProcessHolds(ctx)
+			return err
+		})
+	}()
+	go func() {
+		defer jobs.Done()
+		runPeriodically(ctx, logger, time.Hour, "accrue fines", func(ctx context.Context) error {
+			_, err := fineService.AccrueFines(ctx)
+			return err
+		})
+	}()
+
+	// Setup Gin router. Gin's own request log and debug output are replaced
+	// by structured logs.
+	if os.Getenv(gin.EnvGinMode) == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	r.Use(
+		logging.RequestID(),
+		tracing.Middleware(tracerProvider, cfg.Tracing.ServiceName),
+		logging.Middleware(logger),
+		logging.Recovery(logger, func(c *gin.Context) {
+			problem.Abort(c, problem.New(http.StatusInternalServerError, "An unexpected error occurred"))
+		}),
+		httpMetrics.Middleware(),
+	)
+	r.NoRoute(func(c *gin.Context) {
+		problem.Write(c, problem.New(http.StatusNotFound, "No route matches "+c.Request.Method+" "+c.Request.URL.Path))
+	})
 
-	// Setup Gin router
-	r := gin.Default()
+	// Probes, metrics and docs sit outside /api/v1 so they need no credentials
+	checks, err := readinessChecks(db)
+	if err != nil {
+		fatal(logger, "failed to set up readiness checks", err)
+	}
+	health := api.NewHealthHandler(checks, 3*time.Second)
+	registerServiceRoutes(r, health, metrics.Handler(registry), api.NewDocsHandler())
 
 	// Routes
 	v1 := r.Group("/api/v1")
-	{
-		books := v1.Group("/books")
-		{
-			books.GET("", bookHandler.ListBooks)
-			books.GET("/:id", bookHandler.GetBook)
-			books.POST("", bookHandler.CreateBook)
-			books.PUT("/:id", bookHandler.UpdateBook)
-			books.DELETE("/:id", bookHandler.DeleteBook)
-			books.POST("/:id/issue", bookHandler.IssueBook)
-			books.POST("/:id/return", bookHandler.ReturnBook)
+	if cfg.Auth.Disabled {
+		logger.Warn("authentication is disabled, every route is open")
+		v1.Use(auth.Anonymous())
+	} else {
+		verifier, err := newVerifier(cfg.Auth)
+		if err != nil {
+			fatal(logger, "failed to set up token verification", err)
 		}
+		v1.Use(auth.Middleware(verifier, apiKeyService))
 	}
+	registerRoutes(v1, h)
 
 	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:           r,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("listening", "addr", srv.Addr)
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		fatal(logger, "server failed to start", err)
+	case <-ctx.Done():
+	}
+	// A second signal kills the process without waiting
+	stop()
+
+	logger.Info("shutting down, draining before waiting for in-flight requests",
+		"delay", cfg.Server.ShutdownDelay.String(), "timeout", cfg.Server.ShutdownTimeout.String())
+	err = drain(health, cfg.Server.ShutdownDelay, func() error {
+		return shutdown(srv, db, &jobs, traces, cfg.Server.ShutdownTimeout)
+	})
+	if err != nil {
+		fatal(logger, "unclean shutdown", err)
+	}
+	logger.Info("server stopped")
+}
+
+// fatal logs err and exits
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// readinessChecks returns the dependencies /readyz verifies: the database
+// answers and every migration has been applied
+func readinessChecks(db *gorm.DB) (map[string]api.HealthCheck, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	return map[string]api.HealthCheck{
+		"database": sqlDB.PingContext,
+		"migrations": func(ctx context.Context) error {
+			pending, err := migrator.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			if len(pending) > 0 {
+				return fmt.Errorf("%d pending, next is %04d_%s", len(pending), pending[0].Version, pending[0].Name)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// drain fails the readiness probe, keeps serving for delay so load
+// balancers see it and stop sending requests, and only then calls stop
+func drain(health *api.HealthHandler, delay time.Duration, stop func() error) error {
+	health.SetShuttingDown()
+	time.Sleep(delay)
+	return stop()
+}
+
+// shutdown stops accepting requests and waits up to timeout for in-flight
+// requests and background jobs to finish, so open transactions can commit,
+// then closes the database pool and sends the remaining spans
+func shutdown(srv *http.Server, db *gorm.DB, jobs *sync.WaitGroup, traces *sdktrace.TracerProvider, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	if err := srv.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("requests still running after %s: %w", timeout, err))
+	}
+
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(jobsDone)
+	}()
+	select {
+	case <-jobsDone:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("background jobs still running after %s", timeout))
+	}
+
+	// Close waits for queries already sent to the server to finish
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Close()
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to close database: %w", err))
+	}
+
+	if traces != nil {
+		if err := traces.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush traces: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handlers groups the HTTP handlers registered by registerRoutes
+type handlers struct {
+	book      *api.BookHandler
+	author    *api.AuthorHandler
+	publisher *api.PublisherHandler
+	member    *api.MemberHandler
+	loan      *api.LoanHandler
+	hold      *api.HoldHandler
+	fine      *api.FineHandler
+	search    *api.SearchHandler
+	apiKey    *api.APIKeyHandler
+	self      *api.SelfHandler
+}
+
+// registerServiceRoutes mounts the probes, metrics and API docs on r
+func registerServiceRoutes(r *gin.Engine, health *api.HealthHandler, metricsHandler http.Handler, docs *api.DocsHandler) {
+	r.GET("/healthz", health.Liveness)
+	r.GET("/readyz", health.Readiness)
+	r.GET("/metrics", api.Metrics(metricsHandler))
+	r.GET("/openapi.json", docs.Spec)
+	r.GET("/docs", docs.Index)
+	r.GET("/docs/*any", docs.UI)
+}
+
+// registerRoutes mounts the v1 API on g. Each route declares the permission
+// it requires; g must already authenticate the caller.
+func registerRoutes(g *gin.RouterGroup, h handlers) {
+	var (
+		catalogRead      = auth.Require(auth.PermCatalogRead)
+		catalogWrite     = auth.Require(auth.PermCatalogWrite)
+		catalogDelete    = auth.Require(auth.PermCatalogDelete)
+		circulationRead  = auth.Require(auth.PermCirculationRead)
+		circulationWrite = auth.Require(auth.PermCirculationWrite)
+		holdsWrite       = auth.Require(auth.PermHoldsWrite)
+		membersRead      = auth.Require(auth.PermMembersRead)
+		membersWrite     = auth.Require(auth.PermMembersWrite)
+		membersDelete    = auth.Require(auth.PermMembersDelete)
+		finesWrite       = auth.Require(auth.PermFinesWrite)
+		apiKeysManage    = auth.Require(auth.PermAPIKeysManage)
+		selfRead         = auth.Require(auth.PermSelfRead)
+	)
+
+	books := g.Group("/books")
+	{
+		books.GET("", catalogRead, h.book.ListBooks)
+		books.GET("/:id", catalogRead, h.book.GetBook)
+		books.GET("/isbn/:isbn", catalogRead, h.book.GetBookByISBN)
+		books.POST("", catalogWrite, h.book.CreateBook)
+		books.PUT("/:id", catalogWrite, h.book.UpdateBook)
+		books.DELETE("/:id", catalogDelete, h.book.DeleteBook)
+		books.POST("/:id/issue", circulationWrite, h.book.IssueBook)
+		books.POST("/:id/return", circulationWrite, h.book.ReturnBook)
+		books.POST("/:id/renew", circulationWrite, h.book.RenewBook)
+		books.GET("/:id/loans", circulationRead, h.loan.ListBookLoans)
+		books.POST("/:id/holds", holdsWrite, h.hold.PlaceHold)
+		books.GET("/:id/holds", circulationRead, h.hold.ListBookHolds)
 	}
 
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Server failed to start:", err)
+	authors := g.Group("/authors")
+	{
+		authors.GET("", catalogRead, h.author.ListAuthors)
+		authors.GET("/:id", catalogRead, h.author.GetAuthor)
+		authors.POST("", catalogWrite, h.author.CreateAuthor)
+		authors.PUT("/:id", catalogWrite, h.author.UpdateAuthor)
+		authors.DELETE("/:id", catalogDelete, h.author.DeleteAuthor)
+		authors.GET("/:id/books", catalogRead, h.author.ListAuthorBooks)
+	}
+
+	publishers := g.Group("/publishers")
+	{
+		publishers.GET("", catalogRead, h.publisher.ListPublishers)
+		publishers.GET("/:id", catalogRead, h.publisher.GetPublisher)
+		publishers.POST("", catalogWrite, h.publisher.CreatePublisher)
+		publishers.PUT("/:id", catalogWrite, h.publisher.UpdatePublisher)
+		publishers.DELETE("/:id", catalogDelete, h.publisher.DeletePublisher)
+		publishers.GET("/:id/imprints", catalogRead, h.publisher.ListImprints)
+		publishers.GET("/:id/books", catalogRead, h.publisher.ListPublisherBooks)
+	}
+
+	members := g.Group("/members")
+	{
+		members.GET("", membersRead, h.member.ListMembers)
+		members.GET("/:id", membersRead, h.member.GetMember)
+		members.POST("", membersWrite, h.member.CreateMember)
+		members.PUT("/:id", membersWrite, h.member.UpdateMember)
+		members.DELETE("/:id", membersDelete, h.member.DeleteMember)
+		members.GET("/:id/loans", circulationRead, h.loan.ListMemberLoans)
+		members.GET("/:id/holds", circulationRead, h.hold.ListMemberHolds)
+		members.GET("/:id/fines", circulationRead, h.fine.ListMemberFines)
+	}
+
+	me := g.Group("/me")
+	{
+		me.GET("", selfRead, h.self.GetMe)
+		me.GET("/loans", selfRead, h.self.ListMyLoans)
+		me.GET("/holds", selfRead, h.self.ListMyHolds)
+		me.GET("/fines", selfRead, h.self.ListMyFines)
+	}
+
+	loans := g.Group("/loans")
+	{
+		loans.GET("/:id", circulationRead, h.loan.GetLoan)
+	}
+
+	holds := g.Group("/holds")
+	{
+		holds.GET("/:id", circulationRead, h.hold.GetHold)
+		holds.POST("/:id/cancel", holdsWrite, h.hold.CancelHold)
+	}
+
+	fines := g.Group("/fines")
+	{
+		fines.GET("/:id", circulationRead, h.fine.GetFine)
+		fines.POST("/:id/pay", finesWrite, h.fine.PayFine)
+		fines.POST("/:id/waive", finesWrite, h.fine.WaiveFine)
+	}
+
+	g.GET("/search", catalogRead, h.search.Search)
+
+	apiKeys := g.Group("/api-keys")
+	{
+		apiKeys.GET("", apiKeysManage, h.apiKey.ListAPIKeys)
+		apiKeys.GET("/:id", apiKeysManage, h.apiKey.GetAPIKey)
+		apiKeys.POST("", apiKeysManage, h.apiKey.CreateAPIKey)
+		apiKeys.POST("/:id/revoke", apiKeysManage, h.apiKey.RevokeAPIKey)
+	}
+}
+
+// openDB connects to the database, sizes its connection pool, sends its
+// query log to logger and traces its queries with tracerProvider
+func openDB(cfg config.DatabaseConfig, logger *slog.Logger, tracerProvider trace.TracerProvider) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.URL), &gorm.Config{
+		Logger: logging.NewGormLogger(logger, cfg.SlowQueryThreshold),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(tracing.NewGormPlugin(tracerProvider)); err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	return db, nil
+}
+
+// newTracerProvider returns the provider spans are started from, and the
+// SDK provider batching them to the OTLP collector, which is nil when
+// tracing is disabled
+func newTracerProvider(cfg config.TracingConfig, logger *slog.Logger) (trace.TracerProvider, *sdktrace.TracerProvider, error) {
+	if !cfg.Enabled {
+		return noop.NewTracerProvider(), nil, nil
+	}
+	provider, err := tracing.NewProvider(context.Background(), cfg.Endpoint, cfg.ServiceName, cfg.SampleRatio)
+	if err != nil {
+		return nil, nil, err
+	}
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("trace export failed", "error", err)
+	}))
+	logger.Info("tracing enabled", "endpoint", cfg.Endpoint, "sample_ratio", cfg.SampleRatio)
+	return provider, provider, nil
+}
+
+// newVerifier builds the bearer token verifier. The settings have already
+// been validated, so either a JWKS file or a long enough secret is set.
+func newVerifier(cfg config.AuthConfig) (*auth.Verifier, error) {
+	var keys auth.KeySet
+	if cfg.JWKSFile != "" {
+		fileKeys, err := auth.NewFileKeySet(cfg.JWKSFile, time.Minute)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWKS: %w", err)
+		}
+		keys = fileKeys
+	} else {
+		keys = auth.HMACKeySet([]byte(cfg.JWTSecret))
+	}
+
+	return auth.NewVerifier(auth.VerifierConfig{
+		Keys:     keys,
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+		Leeway:   30 * time.Second,
+	}), nil
+}
+
+// runMigrate runs the migrate subcommand: up, down [N] (default 1) or status
+func runMigrate(db *gorm.DB, logger *slog.Logger, args []string) error {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			logger.Info("applied migration", "version", m.Version, "name", m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("migrate down: invalid step count %q", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(steps)
+		for _, m := range rolledBack {
+			logger.Info("rolled back migration", "version", m.Version, "name", m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to roll back database: %w", err)
+		}
+	case "status":
+		statuses, err := migrator.Status(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to read migration status: %w", err)
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+	default:
+		return fmt.Errorf("usage: %s migrate up|down [N]|status", os.Args[0])
+	}
+	return nil
+}
+
+// runPeriodically calls fn every interval until ctx is cancelled, logging
+// rather than stopping on failure. A run in progress is never interrupted:
+// fn gets a context that is not cancelled along with ctx.
+func runPeriodically(ctx context.Context, logger *slog.Logger, interval time.Duration, name string, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(context.WithoutCancel(ctx)); err != nil {
+				logger.Error("background job failed", "job", name, "error", err)
+			}
+		}
 	}
 }
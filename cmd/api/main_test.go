@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newTestRouter registers the real routes in front of handlers without
+// services. Requests the permission check lets through fail in the handler
+// instead, so any status other than 401/403 means access was granted.
+func newTestRouter(roles ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	h := handlers{
+		book:      api.NewBookHandler(nil),
+		author:    api.NewAuthorHandler(nil),
+		publisher: api.NewPublisherHandler(nil),
+		member:    api.NewMemberHandler(nil),
+		loan:      api.NewLoanHandler(nil),
+		hold:      api.NewHoldHandler(nil, nil),
+		fine:      api.NewFineHandler(nil),
+		search:    api.NewSearchHandler(nil),
+		apiKey:    api.NewAPIKeyHandler(nil),
+		self:      api.NewSelfHandler(nil, nil, nil, nil),
+	}
+
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ interface{}) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	v1 := r.Group("/api/v1")
+	if roles != nil {
+		v1.Use(func(c *gin.Context) {
+			principal := &auth.Principal{Subject: "test", Roles: roles}
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+			c.Next()
+		})
+	}
+	registerRoutes(v1, h)
+	return r
+}
+
+// routeRoles lists, for every route, the roles allowed to call it
+var routeRoles = map[string][]string{
+	"GET /api/v1/books":                   {"admin", "librarian", "member", "read-only"},
+	"GET /api/v1/books/:id":               {"admin", "librarian", "member", "read-only"},
+	"GET /api/v1/books/isbn/:isbn":        {"admin", "librarian", "member", "read-only"},
+	"POST /api/v1/books":                  {"admin", "librarian"},
+	"PUT /api/v1/books/:id":               {"admin", "librarian"},
+	"DELETE /api/v1/books/:id":            {"admin"},
+	"POST /api/v1/books/:id/issue":        {"admin", "librarian"},
+	"POST /api/v1/books/:id/return":       {"admin", "librarian"},
+	"POST /api/v1/books/:id/renew":        {"admin", "librarian"},
+	"GET /api/v1/books/:id/loans":         {"admin", "librarian"},
+	"POST /api/v1/books/:id/holds":        {"admin", "librarian", "member"},
+	"GET /api/v1/books/:id/holds":         {"admin", "librarian"},
+	"GET /api/v1/authors":                 {"admin", "librarian", "member", "read-only"},
+	"GET /api/v1/authors/:id":             {"admin", "librarian", "member", "read-only"},
+	"POST /api/v1/authors":                {"admin", "librarian"},
+	"PUT /api/v1/authors/:id":             {"admin", "librarian"},
+	"DELETE /api/v1/authors/:id":          {"admin"},
+	"GET /api/v1/authors/:id/books":       {"admin", "librarian", "member", "read-only"},
+	"GET /api/v1/publishers":              {"admin", "librarian", "member", "read-only"},
+	"GET /api/v1/publishers/:id":          {"admin", "librarian", "member", "read-only"},
+	"POST /api/v1/publishers":             {"admin", "librarian"},
+	"PUT /api/v1/publishers/:id":          {"admin", "librarian"},
+	"DELETE /api/v1/publishers/:id":       {"admin"},
+	"GET /api/v1/publishers/:id/imprints": {"admin", "librarian", "member", "read-only"},
+	"GET /api/v1/publishers/:id/books":    {"admin", "librarian", "member", "read-only"},
+	"GET /api/v1/members":                 {"admin", "librarian"},
+	"GET /api/v1/members/:id":             {"admin", "librarian"},
+	"POST /api/v1/members":                {"admin", "librarian"},
+	"PUT /api/v1/members/:id":             {"admin", "librarian"},
+	"DELETE /api/v1/members/:id":          {"admin"},
+	"GET /api/v1/members/:id/loans":       {"admin", "librarian"},
+	"GET /api/v1/members/:id/holds":       {"admin", "librarian"},
+	"GET /api/v1/members/:id/fines":       {"admin", "librarian"},
+	"GET /api/v1/me":                      {"admin", "librarian", "member"},
+	"GET /api/v1/me/loans":                {"admin", "librarian", "member"},
+	"GET /api/v1/me/holds":                {"admin", "librarian", "member"},
+	"GET /api/v1/me/fines":                {"admin", "librarian", "member"},
+	"GET /api/v1/loans/:id":               {"admin", "librarian"},
+	"GET /api/v1/holds/:id":               {"admin", "librarian"},
+	"POST /api/v1/holds/:id/cancel":       {"admin", "librarian", "member"},
+	"GET /api/v1/fines/:id":               {"admin", "librarian"},
+	"POST /api/v1/fines/:id/pay":          {"admin", "librarian"},
+	"POST /api/v1/fines/:id/waive":        {"admin", "librarian"},
+	"GET /api/v1/search":                  {"admin", "librarian", "member", "read-only"},
+	"GET /api/v1/api-keys":                {"admin"},
+	"GET /api/v1/api-keys/:id":            {"admin"},
+	"POST /api/v1/api-keys":               {"admin"},
+	"POST /api/v1/api-keys/:id/revoke":    {"admin"},
+}
+
+func TestRoutePermissions(t *testing.T) {
+	allRoles := []string{auth.RoleAdmin, auth.RoleLibrarian, auth.RoleMember, auth.RoleReadOnly}
+
+	routes := newTestRouter().Routes()
+	assert.Len(t, routes, len(routeRoles), "every route needs an entry in routeRoles")
+
+	for _, role := range allRoles {
+		r := newTestRouter(role)
+
+		for _, route := range routes {
+			key := route.Method + " " + route.Path
+			allowed, ok := routeRoles[key]
+			if !assert.True(t, ok, "route %s missing from routeRoles", key) {
+				continue
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(route.Method, strings.ReplaceAll(route.Path, ":id", "not-a-uuid"), nil)
+			r.ServeHTTP(w, req)
+
+			if contains(allowed, role) {
+				assert.NotEqual(t, http.StatusForbidden, w.Code, "%s should be allowed %s", role, key)
+			} else {
+				assert.Equal(t, http.StatusForbidden, w.Code, "%s should be denied %s", role, key)
+			}
+		}
+	}
+}
+
+func TestRoutePermissions_Unauthenticated(t *testing.T) {
+	r := newTestRouter()
+
+	for _, route := range r.Routes() {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(route.Method, strings.ReplaceAll(route.Path, ":id", "not-a-uuid"), nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, route.Method+" "+route.Path)
+	}
+}
+
+func TestRoutePermissions_UnknownRole(t *testing.T) {
+	r := newTestRouter("superuser")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/books", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRoutePermissions_APIKeyScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	v1 := r.Group("/api/v1")
+	v1.Use(func(c *gin.Context) {
+		principal := &auth.Principal{Subject: "api-key:test", Scopes: []auth.Permission{auth.PermCirculationWrite}}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	})
+	registerRoutes(v1, handlers{book: api.NewBookHandler(nil)})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/books/not-a-uuid/issue", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "scope grants issue")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/api/v1/books/not-a-uuid", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code, "scope does not grant delete")
+}
+
+// TestRoutesMatchOpenAPISpec fails when a route is added or removed without
+// documenting it; run go generate ./internal/api after updating the handler
+// annotations
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]struct {
+			Security []map[string][]string `json:"security"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(api.OpenAPISpec(), &spec))
+
+	documented := map[string]bool{}
+	for path, methods := range spec.Paths {
+		for method, op := range methods {
+			key := strings.ToUpper(method) + " " + path
+			documented[key] = true
+			assert.Equal(t, strings.HasPrefix(path, "/api/v1/"), len(op.Security) > 0,
+				"%s should list credentials exactly when it is under /api/v1", key)
+		}
+	}
+
+	r := newTestRouter()
+	registerServiceRoutes(r, api.NewHealthHandler(nil, time.Second), http.NotFoundHandler(), api.NewDocsHandler())
+	served := map[string]bool{}
+	for _, route := range r.Routes() {
+		// Gin writes parameters as :id or *any, OpenAPI as {id}
+		segments := strings.Split(route.Path, "/")
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+				segments[i] = "{" + segment[1:] + "}"
+			}
+		}
+		served[route.Method+" "+strings.Join(segments, "/")] = true
+	}
+
+	for key := range served {
+		assert.True(t, documented[key], "route %s is not in the OpenAPI spec", key)
+	}
+	for key := range documented {
+		assert.True(t, served[key], "%s is in the OpenAPI spec but not routed", key)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestShutdown(t *testing.T) {
+	// The pool never connects, so no database is needed
+	db, err := gorm.Open(postgres.Open("postgres://localhost/unused"), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	})}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(ln)
+
+	// A job that is mid-run when shutdown starts
+	var jobs sync.WaitGroup
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		time.Sleep(100 * time.Millisecond)
+	}()
+
+	status := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			status <- 0
+			return
+		}
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+	<-started
+
+	require.NoError(t, shutdown(srv, db, &jobs, nil, 5*time.Second))
+	assert.Equal(t, http.StatusNoContent, <-status, "in-flight request should complete")
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	assert.ErrorContains(t, sqlDB.Ping(), "database is closed")
+
+	_, err = http.Get("http://" + ln.Addr().String())
+	assert.Error(t, err, "server should no longer accept connections")
+}
+
+func TestDrain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	health := api.NewHealthHandler(nil, time.Second)
+	r := gin.New()
+	r.GET("/readyz", health.Readiness)
+	ready := func() int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/readyz", nil)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	require.Equal(t, http.StatusOK, ready())
+
+	const delay = 100 * time.Millisecond
+	start := time.Now()
+	var stoppedAfter time.Duration
+	var readyWhenStopped int
+	err := drain(health, delay, func() error {
+		stoppedAfter = time.Since(start)
+		readyWhenStopped = ready()
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, stoppedAfter, delay, "stop must wait for the drain delay")
+	assert.Equal(t, http.StatusServiceUnavailable, readyWhenStopped, "readiness must fail before the server stops")
+}
+
+func TestShutdown_Timeout(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("postgres://localhost/unused"), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var jobs sync.WaitGroup
+	jobs.Add(1)
+	defer jobs.Done()
+
+	err = shutdown(&http.Server{}, db, &jobs, nil, 50*time.Millisecond)
+	assert.ErrorContains(t, err, "background jobs still running")
+}
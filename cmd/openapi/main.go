@@ -0,0 +1,45 @@
+// Command openapi regenerates internal/api/docs/openapi.json from the
+// handler annotations. Run it through go generate ./internal/api.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/library-api/internal/openapi"
+)
+
+func main() {
+	root := flag.String("root", findRoot(), "module root")
+	out := flag.String("o", "", "output file (default internal/api/docs/openapi.json under the root)")
+	flag.Parse()
+
+	spec, err := openapi.Generate(*root)
+	if err != nil {
+		log.Fatalf("openapi: %v", err)
+	}
+	if *out == "" {
+		*out = filepath.Join(*root, "internal", "api", "docs", "openapi.json")
+	}
+	if err := os.WriteFile(*out, spec, 0o644); err != nil {
+		log.Fatalf("openapi: %v", err)
+	}
+}
+
+// findRoot walks up from the working directory to the directory holding go.mod
+func findRoot() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return d
+		}
+		if filepath.Dir(d) == d {
+			return dir
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/auth"
+	"github.com/library-api/internal/service"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+	validate      *validator.Validate
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	validate := newValidator()
+	validate.RegisterValidation("api_key_scope", func(fl validator.FieldLevel) bool {
+		return service.IsGrantableScope(fl.Field().String())
+	})
+
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validate:      validate,
+	}
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description get API keys, newest first. Secrets are never returned
+// @Tags api-keys
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success 200 {object} PageResponse{data=[]models.APIKey}
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	keys, total, err := h.apiKeyService.ListAPIKeys(c.Request.Context(), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  keys,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetAPIKey godoc
+// @Summary Get an API key
+// @Description get API key by ID. The secret is never returned
+// @Tags api-keys
+// @Accept  json
+// @Produce  json
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.APIKey
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/api-keys/{id} [get]
+func (h *APIKeyHandler) GetAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	key, err := h.apiKeyService.GetAPIKey(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description create a key for a machine client. The response is the only place the key is shown
+// @Tags api-keys
+// @Accept  json
+// @Produce  json
+// @Param key body CreateAPIKeyRequest true "Create API key"
+// @Success 201 {object} CreateAPIKeyResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	createdBy := ""
+	if principal, ok := auth.CurrentPrincipal(c); ok {
+		createdBy = principal.Subject
+	}
+
+	key, plaintext, err := h.apiKeyService.CreateAPIKey(c.Request.Context(), req.Name, req.Scopes, req.ExpiresAt, createdBy)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateAPIKeyResponse{APIKey: *key, Key: plaintext})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description revoke an API key so it can no longer authenticate
+// @Tags api-keys
+// @Accept  json
+// @Produce  json
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.APIKey
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/api-keys/{id}/revoke [post]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	key, err := h.apiKeyService.RevokeAPIKey(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
@@ -0,0 +1,178 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/auth"
+	"github.com/library-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time, createdBy string) (*models.APIKey, string, error) {
+	args := m.Called(ctx, name, scopes, expiresAt, createdBy)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*models.APIKey), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyService) GetAPIKey(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) ListAPIKeys(ctx context.Context, page, limit int) ([]models.APIKey, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]models.APIKey), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth.Principal), args.Error(1)
+}
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAPIKeyService)
+	handler := api.NewAPIKeyHandler(mockService)
+
+	scopes := []string{"catalog:read", "holds:manage"}
+	key := &models.APIKey{ID: uuid.New(), Name: "Kiosk", Prefix: "abc123", Hash: "secret-hash", Scopes: scopes}
+	mockService.On("CreateAPIKey", mock.Anything, "Kiosk", scopes, (*time.Time)(nil), "admin-user").Return(key, "lib_abc123_secret", nil)
+
+	r := gin.Default()
+	r.POST("/api-keys", func(c *gin.Context) {
+		principal := &auth.Principal{Subject: "admin-user", Roles: []string{auth.RoleAdmin}}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+	}, handler.CreateAPIKey)
+
+	body, _ := json.Marshal(api.CreateAPIKeyRequest{Name: "Kiosk", Scopes: scopes})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api-keys", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "lib_abc123_secret", response["key"])
+	assert.Equal(t, "abc123", response["prefix"])
+	assert.NotContains(t, w.Body.String(), "secret-hash", "the hash must never be returned")
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_CreateAPIKey_InvalidScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAPIKeyService)
+	handler := api.NewAPIKeyHandler(mockService)
+
+	r := gin.Default()
+	r.POST("/api-keys", handler.CreateAPIKey)
+
+	for _, scopes := range [][]string{
+		nil,
+		{"catalog:read", "superpowers"},
+		// Keys cannot mint keys
+		{"api-keys:manage"},
+		// Keys have no member of their own to act for
+		{"self:read"},
+		{"catalog:read", "holds:write"},
+	} {
+		body, _ := json.Marshal(api.CreateAPIKeyRequest{Name: "Kiosk", Scopes: scopes})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api-keys", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 400, w.Code, "%v", scopes)
+	}
+	mockService.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyHandler_ListAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAPIKeyService)
+	handler := api.NewAPIKeyHandler(mockService)
+
+	keys := []models.APIKey{{ID: uuid.New(), Name: "Importer", Prefix: "def456"}}
+	mockService.On("ListAPIKeys", mock.Anything, 1, 10).Return(keys, int64(1), nil)
+
+	r := gin.Default()
+	r.GET("/api-keys", handler.ListAPIKeys)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api-keys", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), response["total"])
+}
+
+func TestAPIKeyHandler_RevokeAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAPIKeyService)
+	handler := api.NewAPIKeyHandler(mockService)
+
+	now := time.Now()
+	id := uuid.New()
+	mockService.On("RevokeAPIKey", mock.Anything, id).Return(&models.APIKey{ID: id, RevokedAt: &now}, nil)
+	missing := uuid.New()
+	mockService.On("RevokeAPIKey", mock.Anything, missing).Return(nil, apperr.NotFound("API key not found"))
+
+	r := gin.Default()
+	r.POST("/api-keys/:id/revoke", handler.RevokeAPIKey)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api-keys/"+id.String()+"/revoke", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api-keys/"+missing.String()+"/revoke", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 404, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api-keys/not-a-uuid/revoke", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+}
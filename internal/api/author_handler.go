@@ -0,0 +1,253 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/service"
+)
+
+type AuthorHandler struct {
+	authorService service.AuthorService
+	validate      *validator.Validate
+}
+
+func NewAuthorHandler(authorService service.AuthorService) *AuthorHandler {
+	return &AuthorHandler{
+		authorService: authorService,
+		validate:      newValidator(),
+	}
+}
+
+// ListAuthors godoc
+// @Summary List all authors
+// @Description get authors
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success 200 {object} PageResponse{data=[]models.Author}
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/authors [get]
+func (h *AuthorHandler) ListAuthors(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	authors, total, err := h.authorService.ListAuthors(c.Request.Context(), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  authors,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetAuthor godoc
+// @Summary Get an author
+// @Description get author by ID
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Author ID"
+// @Success 200 {object} models.Author
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/authors/{id} [get]
+func (h *AuthorHandler) GetAuthor(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid author ID")
+		return
+	}
+
+	author, err := h.authorService.GetAuthor(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, author)
+}
+
+// CreateAuthor godoc
+// @Summary Create an author
+// @Description create new author
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param author body CreateAuthorRequest true "Create author"
+// @Success 201 {object} models.Author
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/authors [post]
+func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
+	var req CreateAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	author := models.Author{
+		Name:      req.Name,
+		Biography: req.Biography,
+	}
+
+	if err := h.authorService.CreateAuthor(c.Request.Context(), &author); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, author)
+}
+
+// UpdateAuthor godoc
+// @Summary Update an author
+// @Description update author by ID
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Author ID"
+// @Param author body UpdateAuthorRequest true "Update author"
+// @Success 200 {object} models.Author
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/authors/{id} [put]
+func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid author ID")
+		return
+	}
+
+	var req UpdateAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	// Fetch the existing author from DB first
+	author, err := h.authorService.GetAuthor(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Update allowed fields
+	author.Name = req.Name
+	author.Biography = req.Biography
+
+	if err := h.authorService.UpdateAuthor(c.Request.Context(), author); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, author)
+}
+
+// DeleteAuthor godoc
+// @Summary Delete an author
+// @Description delete author by ID
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Author ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/authors/{id} [delete]
+func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid author ID")
+		return
+	}
+
+	if err := h.authorService.DeleteAuthor(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListAuthorBooks godoc
+// @Summary List an author's books
+// @Description get the books written by an author
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Author ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success 200 {object} PageResponse{data=[]models.Book}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/authors/{id}/books [get]
+func (h *AuthorHandler) ListAuthorBooks(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid author ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	books, total, err := h.authorService.ListAuthorBooks(c.Request.Context(), id, page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  books,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
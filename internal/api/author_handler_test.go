@@ -0,0 +1,245 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuthorService struct {
+	mock.Mock
+}
+
+func (m *MockAuthorService) CreateAuthor(ctx context.Context, author *models.Author) error {
+	args := m.Called(ctx, author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorService) UpdateAuthor(ctx context.Context, author *models.Author) error {
+	args := m.Called(ctx, author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorService) DeleteAuthor(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthorService) GetAuthor(ctx context.Context, id uuid.UUID) (*models.Author, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Author), args.Error(1)
+}
+
+func (m *MockAuthorService) ListAuthors(ctx context.Context, page, limit int) ([]models.Author, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]models.Author), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAuthorService) ListAuthorBooks(ctx context.Context, id uuid.UUID, page, limit int) ([]models.Book, int64, error) {
+	args := m.Called(ctx, id, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]models.Book), args.Get(1).(int64), args.Error(2)
+}
+
+func TestAuthorHandler_ListAuthors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAuthorService)
+	handler := api.NewAuthorHandler(mockService)
+
+	authors := []models.Author{{Name: "Test Author"}}
+	mockService.On("ListAuthors", mock.Anything, 2, 5).Return(authors, int64(6), nil)
+
+	r := gin.Default()
+	r.GET("/authors", handler.ListAuthors)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/authors?page=2&limit=5", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(6), response["total"])
+	assert.Equal(t, float64(2), response["page"])
+	assert.Equal(t, float64(5), response["limit"])
+}
+
+func TestAuthorHandler_CreateAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAuthorService)
+	handler := api.NewAuthorHandler(mockService)
+
+	mockService.On("CreateAuthor", mock.Anything, mock.AnythingOfType("*models.Author")).Return(nil)
+
+	r := gin.Default()
+	r.POST("/authors", handler.CreateAuthor)
+
+	body, _ := json.Marshal(api.CreateAuthorRequest{Name: "Test Author", Biography: "Bio"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/authors", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+
+	var response models.Author
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Author", response.Name)
+	assert.Equal(t, "Bio", response.Biography)
+}
+
+func TestAuthorHandler_CreateAuthor_MissingName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAuthorService)
+	handler := api.NewAuthorHandler(mockService)
+
+	r := gin.Default()
+	r.POST("/authors", handler.CreateAuthor)
+
+	body, _ := json.Marshal(api.CreateAuthorRequest{Biography: "Bio"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/authors", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	mockService.AssertNotCalled(t, "CreateAuthor", mock.Anything)
+}
+
+func TestAuthorHandler_UpdateAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAuthorService)
+	handler := api.NewAuthorHandler(mockService)
+
+	authorID := uuid.New()
+	existing := &models.Author{ID: authorID, Name: "Old Name"}
+
+	mockService.On("GetAuthor", mock.Anything, authorID).Return(existing, nil)
+	mockService.On("UpdateAuthor", mock.Anything, mock.AnythingOfType("*models.Author")).Return(nil)
+
+	r := gin.Default()
+	r.PUT("/authors/:id", handler.UpdateAuthor)
+
+	body, _ := json.Marshal(api.UpdateAuthorRequest{Name: "New Name", Biography: "New Bio"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/authors/"+authorID.String(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Author
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "New Name", response.Name)
+	assert.Equal(t, "New Bio", response.Biography)
+}
+
+func TestAuthorHandler_DeleteAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAuthorService)
+	handler := api.NewAuthorHandler(mockService)
+
+	authorID := uuid.New()
+	mockService.On("DeleteAuthor", mock.Anything, authorID).Return(nil)
+
+	r := gin.Default()
+	r.DELETE("/authors/:id", handler.DeleteAuthor)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/authors/"+authorID.String(), nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 204, w.Code)
+}
+
+func TestAuthorHandler_DeleteAuthor_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAuthorService)
+	handler := api.NewAuthorHandler(mockService)
+
+	authorID := uuid.New()
+	mockService.On("DeleteAuthor", mock.Anything, authorID).Return(apperr.NotFound("author not found"))
+
+	r := gin.Default()
+	r.DELETE("/authors/:id", handler.DeleteAuthor)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/authors/"+authorID.String(), nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestAuthorHandler_GetAuthor_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAuthorService)
+	handler := api.NewAuthorHandler(mockService)
+
+	authorID := uuid.New()
+	mockService.On("GetAuthor", mock.Anything, authorID).Return(nil, apperr.NotFound("author not found"))
+
+	r := gin.Default()
+	r.GET("/authors/:id", handler.GetAuthor)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/authors/"+authorID.String(), nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestAuthorHandler_ListAuthorBooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockAuthorService)
+	handler := api.NewAuthorHandler(mockService)
+
+	authorID := uuid.New()
+	books := []models.Book{{Title: "Book One", AuthorID: authorID}, {Title: "Book Two", AuthorID: authorID}}
+	mockService.On("ListAuthorBooks", mock.Anything, authorID, 1, 10).Return(books, int64(2), nil)
+
+	r := gin.Default()
+	r.GET("/authors/:id/books", handler.ListAuthorBooks)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/authors/"+authorID.String()+"/books", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response struct {
+		Data  []models.Book `json:"data"`
+		Total int64         `json:"total"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), response.Total)
+	assert.Len(t, response.Data, 2)
+}
@@ -2,12 +2,13 @@ package api
 
 import (
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
 	"github.com/library-api/internal/service"
 )
 
@@ -19,38 +20,133 @@ type BookHandler struct {
 func NewBookHandler(bookService service.BookService) *BookHandler {
 	return &BookHandler{
 		bookService: bookService,
-		validate:    validator.New(),
+		validate:    newValidator(),
 	}
 }
 
 // ListBooks godoc
 // @Summary List all books
-// @Description get books
+// @Description get books, optionally filtered and sorted
 // @Tags books
 // @Accept  json
 // @Produce  json
-// @Param page query int false "Page number"
-// @Param limit query int false "Number of items per page"
-// @Success 200 {object} []models.Book
-// @Router /books [get]
+// @Param page query int false "Page number (max 10000)"
+// @Param limit query int false "Number of items per page (max 100)"
+// @Param genre query string false "Genre, case-insensitive"
+// @Param year_from query int false "Earliest publication year"
+// @Param year_to query int false "Latest publication year"
+// @Param author_id query string false "Author ID"
+// @Param publisher_id query string false "Publisher ID"
+// @Param available query bool false "Only books with (true) or without (false) copies on the shelf"
+// @Param q query string false "Matches title or author name"
+// @Param sort query string false "title, year or created_at (default)"
+// @Param order query string false "asc (default) or desc"
+// @Param cursor query string false "Keyset pagination token from next_cursor/prev_cursor; empty for the first page. Replaces page"
+// @Param include_total query bool false "Count the total in cursor mode"
+// @Success 200 {object} PageResponse{data=[]models.Book} "Page mode; with cursor set the body has next_cursor and prev_cursor instead of page, and total only with include_total"
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books [get]
 func (h *BookHandler) ListBooks(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	var query ListBooksQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(query); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	sortBy := query.Sort
+	if sortBy == "" {
+		sortBy = repository.BookSortCreatedAt
+	}
+	filter := repository.BookFilter{
+		Genre:     query.Genre,
+		YearFrom:  query.YearFrom,
+		YearTo:    query.YearTo,
+		Available: query.Available,
+		Query:     strings.TrimSpace(query.Q),
+		SortBy:    sortBy,
+		SortDesc:  query.Order == "desc",
+	}
+	// Already validated as UUIDs above
+	if query.AuthorID != "" {
+		filter.AuthorID = uuid.MustParse(query.AuthorID)
+	}
+	if query.PublisherID != "" {
+		filter.PublisherID = uuid.MustParse(query.PublisherID)
+	}
 
-	books, total, err := h.bookService.ListBooks(page, limit)
+	if _, cursorMode := c.GetQuery("cursor"); cursorMode {
+		if _, hasPage := c.GetQuery("page"); hasPage {
+			respondProblem(c, http.StatusBadRequest, "page cannot be combined with cursor")
+			return
+		}
+		h.listBooksByCursor(c, filter, query)
+		return
+	}
+
+	books, total, err := h.bookService.ListBooks(c.Request.Context(), filter, query.Page, query.Limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":  books,
 		"total": total,
-		"page":  page,
-		"limit": limit,
+		"page":  query.Page,
+		"limit": query.Limit,
 	})
 }
 
+// listBooksByCursor serves ListBooks in keyset pagination mode
+func (h *BookHandler) listBooksByCursor(c *gin.Context, filter repository.BookFilter, query ListBooksQuery) {
+	var cursor *repository.BookCursor
+	if query.Cursor != "" {
+		var err error
+		cursor, err = repository.DecodeBookCursor(query.Cursor)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		if cursor.SortBy != filter.SortBy || cursor.SortDesc != filter.SortDesc {
+			respondProblem(c, http.StatusBadRequest, "Cursor does not match the requested sort order")
+			return
+		}
+	}
+
+	page, err := h.bookService.ListBooksByCursor(c.Request.Context(), filter, cursor, query.Limit, query.IncludeTotal)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := gin.H{
+		"data":        page.Books,
+		"limit":       query.Limit,
+		"next_cursor": nil,
+		"prev_cursor": nil,
+	}
+	if page.Next != nil {
+		response["next_cursor"] = page.Next.Encode()
+	}
+	if page.Prev != nil {
+		response["prev_cursor"] = page.Prev.Encode()
+	}
+	if page.Total != nil {
+		response["total"] = *page.Total
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetBook godoc
 // @Summary Get a book
 // @Description get book by ID
@@ -59,17 +155,48 @@ func (h *BookHandler) ListBooks(c *gin.Context) {
 // @Produce  json
 // @Param id path string true "Book ID"
 // @Success 200 {object} models.Book
-// @Router /books/{id} [get]
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/{id} [get]
 func (h *BookHandler) GetBook(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		respondProblem(c, http.StatusBadRequest, "Invalid book ID")
 		return
 	}
 
-	book, err := h.bookService.GetBook(id)
+	book, err := h.bookService.GetBook(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, book)
+}
+
+// GetBookByISBN godoc
+// @Summary Get a book by ISBN
+// @Description get book by ISBN-10 or ISBN-13, with or without hyphens
+// @Tags books
+// @Accept  json
+// @Produce  json
+// @Param isbn path string true "ISBN-10 or ISBN-13"
+// @Success 200 {object} models.Book
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/isbn/{isbn} [get]
+func (h *BookHandler) GetBookByISBN(c *gin.Context) {
+	book, err := h.bookService.GetBookByISBN(c.Request.Context(), c.Param("isbn"))
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -84,16 +211,22 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 // @Produce  json
 // @Param book body CreateBookRequest true "Create book"
 // @Success 201 {object} models.Book
-// @Router /books [post]
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books [post]
 func (h *BookHandler) CreateBook(c *gin.Context) {
 	var req CreateBookRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
@@ -107,8 +240,8 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		Quantity:    req.Quantity,
 	}
 
-	if err := h.bookService.CreateBook(&book); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.bookService.CreateBook(c.Request.Context(), &book); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -124,29 +257,36 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 // @Param id path string true "Book ID"
 // @Param book body UpdateBookRequest true "Update book"
 // @Success 200 {object} models.Book
-// @Router /books/{id} [put]
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/{id} [put]
 func (h *BookHandler) UpdateBook(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		respondProblem(c, http.StatusBadRequest, "Invalid book ID")
 		return
 	}
 
 	var req UpdateBookRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
 	// Fetch the existing book from DB first
-	book, err := h.bookService.GetBook(id)
+	book, err := h.bookService.GetBook(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		respondError(c, err)
 		return
 	}
 
@@ -159,8 +299,8 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 	book.Genre = req.Genre
 	book.Quantity = req.Quantity
 
-	if err := h.bookService.UpdateBook(book); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.bookService.UpdateBook(c.Request.Context(), book); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -175,16 +315,23 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 // @Produce  json
 // @Param id path string true "Book ID"
 // @Success 204 "No Content"
-// @Router /books/{id} [delete]
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/{id} [delete]
 func (h *BookHandler) DeleteBook(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		respondProblem(c, http.StatusBadRequest, "Invalid book ID")
 		return
 	}
 
-	if err := h.bookService.DeleteBook(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.bookService.DeleteBook(c.Request.Context(), id); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -193,25 +340,42 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 
 // IssueBook godoc
 // @Summary Issue a book
-// @Description increment the QuantityIssued of a book
+// @Description issue a copy of a book to a member
 // @Tags books
 // @Accept  json
 // @Produce  json
 // @Param id path string true "Book ID"
+// @Param loan body IssueBookRequest true "Member borrowing the book"
 // @Success 200 {object} models.Book
-// @Failure 400 {object} map[string]string
-// @Failure 409 {object} map[string]string
-// @Router /books/{id}/issue [post]
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/{id}/issue [post]
 func (h *BookHandler) IssueBook(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		respondProblem(c, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	var req IssueBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
 		return
 	}
 
-	book, err := h.bookService.IssueBook(id)
+	book, err := h.bookService.IssueBook(c.Request.Context(), id, req.MemberID)
 	if err != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -220,27 +384,88 @@ func (h *BookHandler) IssueBook(c *gin.Context) {
 
 // ReturnBook godoc
 // @Summary Return a book
-// @Description decrement the QuantityIssued of a book
+// @Description return a member's issued copy of a book
 // @Tags books
 // @Accept  json
 // @Produce  json
 // @Param id path string true "Book ID"
+// @Param loan body ReturnBookRequest true "Member returning the book"
 // @Success 200 {object} models.Book
-// @Failure 400 {object} map[string]string
-// @Failure 409 {object} map[string]string
-// @Router /books/{id}/return [post]
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/{id}/return [post]
 func (h *BookHandler) ReturnBook(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		respondProblem(c, http.StatusBadRequest, "Invalid book ID")
 		return
 	}
 
-	book, err := h.bookService.ReturnBook(id)
+	var req ReturnBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	book, err := h.bookService.ReturnBook(c.Request.Context(), id, req.MemberID)
 	if err != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, book)
 }
+
+// RenewBook godoc
+// @Summary Renew a loan
+// @Description extend the due date of a member's issued copy of a book
+// @Tags books
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Book ID"
+// @Param loan body RenewBookRequest true "Member renewing the loan"
+// @Success 200 {object} models.Loan
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/{id}/renew [post]
+func (h *BookHandler) RenewBook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	var req RenewBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	loan, err := h.bookService.RenewBook(c.Request.Context(), id, req.MemberID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, loan)
+}
@@ -2,15 +2,22 @@ package api_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/apperr"
 	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/problem"
+	"github.com/library-api/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,44 +26,68 @@ type MockBookService struct {
 	mock.Mock
 }
 
-func (m *MockBookService) CreateBook(book *models.Book) error {
-	args := m.Called(book)
+func (m *MockBookService) CreateBook(ctx context.Context, book *models.Book) error {
+	args := m.Called(ctx, book)
 	return args.Error(0)
 }
 
-func (m *MockBookService) UpdateBook(book *models.Book) error {
-	args := m.Called(book)
+func (m *MockBookService) UpdateBook(ctx context.Context, book *models.Book) error {
+	args := m.Called(ctx, book)
 	return args.Error(0)
 }
 
-func (m *MockBookService) DeleteBook(id uuid.UUID) error {
-	args := m.Called(id)
+func (m *MockBookService) DeleteBook(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockBookService) GetBook(id uuid.UUID) (*models.Book, error) {
-	args := m.Called(id)
+func (m *MockBookService) GetBook(ctx context.Context, id uuid.UUID) (*models.Book, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Book), args.Error(1)
 }
 
-func (m *MockBookService) ListBooks(page, limit int) ([]models.Book, int64, error) {
-	args := m.Called(page, limit)
+func (m *MockBookService) GetBookByISBN(ctx context.Context, number string) (*models.Book, error) {
+	args := m.Called(ctx, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Book), args.Error(1)
+}
+
+func (m *MockBookService) ListBooks(ctx context.Context, filter repository.BookFilter, page, limit int) ([]models.Book, int64, error) {
+	args := m.Called(ctx, filter, page, limit)
 	return args.Get(0).([]models.Book), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockBookService) IssueBook(id uuid.UUID) (*models.Book, error) {
-	args := m.Called(id)
+func (m *MockBookService) ListBooksByCursor(ctx context.Context, filter repository.BookFilter, cursor *repository.BookCursor, limit int, withTotal bool) (*repository.BookPage, error) {
+	args := m.Called(ctx, filter, cursor, limit, withTotal)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.BookPage), args.Error(1)
+}
+
+func (m *MockBookService) IssueBook(ctx context.Context, id, memberID uuid.UUID) (*models.Book, error) {
+	args := m.Called(ctx, id, memberID)
 	return args.Get(0).(*models.Book), args.Error(1)
 }
 
-func (m *MockBookService) ReturnBook(id uuid.UUID) (*models.Book, error) {
-	args := m.Called(id)
+func (m *MockBookService) ReturnBook(ctx context.Context, id, memberID uuid.UUID) (*models.Book, error) {
+	args := m.Called(ctx, id, memberID)
 	return args.Get(0).(*models.Book), args.Error(1)
 }
 
+func (m *MockBookService) RenewBook(ctx context.Context, id, memberID uuid.UUID) (*models.Loan, error) {
+	args := m.Called(ctx, id, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Loan), args.Error(1)
+}
+
 func TestBookHandler_ListBooks(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -66,13 +97,13 @@ func TestBookHandler_ListBooks(t *testing.T) {
 	books := []models.Book{
 		{
 			Title: "Test Book 1",
-			ISBN:  "1234567890123",
+			ISBN:  "9780306406157",
 			Genre: "Test",
 			Year:  2025,
 		},
 	}
 
-	mockService.On("ListBooks", 1, 10).Return(books, int64(1), nil)
+	mockService.On("ListBooks", mock.Anything, repository.BookFilter{SortBy: repository.BookSortCreatedAt}, 1, 10).Return(books, int64(1), nil)
 
 	r := gin.Default()
 	r.GET("/books", handler.ListBooks)
@@ -92,6 +123,143 @@ func TestBookHandler_ListBooks(t *testing.T) {
 	assert.Equal(t, float64(10), response["limit"])
 }
 
+func TestBookHandler_ListBooks_Filters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	authorID := uuid.New()
+	available := true
+	expected := repository.BookFilter{
+		Genre:     "Fantasy",
+		YearFrom:  1990,
+		YearTo:    2000,
+		AuthorID:  authorID,
+		Available: &available,
+		Query:     "ring",
+		SortBy:    repository.BookSortYear,
+		SortDesc:  true,
+	}
+	mockService.On("ListBooks", mock.Anything, expected, 2, 5).Return([]models.Book{}, int64(0), nil)
+
+	r := gin.Default()
+	r.GET("/books", handler.ListBooks)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books?page=2&limit=5&genre=Fantasy&year_from=1990&year_to=2000&author_id="+authorID.String()+"&available=true&q=ring&sort=year&order=desc", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookHandler_ListBooks_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	r := gin.Default()
+	r.GET("/books", handler.ListBooks)
+
+	for _, query := range []string{
+		"sort=isbn",
+		"order=sideways",
+		"limit=500",
+		"page=10001",
+		"page=9223372036854775807",
+		"year_from=2000&year_to=1990",
+		"author_id=not-a-uuid",
+		"available=maybe",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books?"+query, nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 400, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "ListBooks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBookHandler_ListBooks_Cursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	filter := repository.BookFilter{SortBy: repository.BookSortTitle}
+	next := &repository.BookCursor{SortBy: repository.BookSortTitle, Title: "B", ID: uuid.New()}
+	total := int64(3)
+	mockService.On("ListBooksByCursor", mock.Anything, filter, (*repository.BookCursor)(nil), 2, true).Return(&repository.BookPage{
+		Books: []models.Book{{Title: "A"}, {Title: "B"}},
+		Next:  next,
+		Total: &total,
+	}, nil)
+
+	r := gin.Default()
+	r.GET("/books", handler.ListBooks)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books?cursor=&limit=2&sort=title&include_total=true", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response struct {
+		Data       []models.Book `json:"data"`
+		NextCursor *string       `json:"next_cursor"`
+		PrevCursor *string       `json:"prev_cursor"`
+		Total      int64         `json:"total"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, int64(3), response.Total)
+	assert.Nil(t, response.PrevCursor)
+	if assert.NotNil(t, response.NextCursor) {
+		decoded, err := repository.DecodeBookCursor(*response.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, next, decoded)
+	}
+
+	// Following the token passes the decoded cursor back
+	mockService.On("ListBooksByCursor", mock.Anything, filter, next, 2, false).Return(&repository.BookPage{Books: []models.Book{{Title: "C"}}}, nil)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/books?limit=2&sort=title&cursor="+*response.NextCursor, nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookHandler_ListBooks_InvalidCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	r := gin.Default()
+	r.GET("/books", handler.ListBooks)
+
+	titleCursor := (&repository.BookCursor{SortBy: repository.BookSortTitle, Title: "A", ID: uuid.New()}).Encode()
+	for _, query := range []string{
+		"cursor=not-a-cursor",
+		"cursor=&page=2",
+		// Cursor was issued for a different sort
+		"sort=year&cursor=" + titleCursor,
+		"sort=title&order=desc&cursor=" + titleCursor,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books?"+query, nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 400, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "ListBooksByCursor", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestBookHandler_CreateBook(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -100,7 +268,7 @@ func TestBookHandler_CreateBook(t *testing.T) {
 
 	book := models.Book{
 		Title:       "Test Book",
-		ISBN:        "1234567890123",
+		ISBN:        "9780306406157",
 		Genre:       "Fiction",
 		Year:        2025,
 		Quantity:    10,
@@ -110,7 +278,7 @@ func TestBookHandler_CreateBook(t *testing.T) {
 		Publisher:   models.Publisher{Name: "Test Publisher"},
 	}
 
-	mockService.On("CreateBook", mock.AnythingOfType("*models.Book")).Return(nil)
+	mockService.On("CreateBook", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil)
 
 	r := gin.Default()
 	r.POST("/books", handler.CreateBook)
@@ -139,7 +307,7 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 	existingBook := &models.Book{
 		ID:          bookID,
 		Title:       "Old Title",
-		ISBN:        "9876543210123",
+		ISBN:        "9780804429573",
 		Genre:       "Non-Fiction",
 		Year:        2024,
 		Quantity:    3,
@@ -150,7 +318,7 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 	// Updated data
 	updateReq := api.UpdateBookRequest{
 		Title:       "Updated Test Book",
-		ISBN:        "1234567890123",
+		ISBN:        "9780306406157",
 		Genre:       "Fiction",
 		Year:        2025,
 		Quantity:    5,
@@ -159,9 +327,9 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 	}
 
 	// Mock GetBook to return the existing book
-	mockService.On("GetBook", bookID).Return(existingBook, nil)
+	mockService.On("GetBook", mock.Anything, bookID).Return(existingBook, nil)
 	// Mock UpdateBook to succeed
-	mockService.On("UpdateBook", mock.AnythingOfType("*models.Book")).Return(nil)
+	mockService.On("UpdateBook", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil)
 
 	r := gin.Default()
 	r.PUT("/books/:id", handler.UpdateBook)
@@ -192,7 +360,7 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 
 	bookID := uuid.New()
 
-	mockService.On("DeleteBook", bookID).Return(nil)
+	mockService.On("DeleteBook", mock.Anything, bookID).Return(nil)
 
 	r := gin.Default()
 	r.DELETE("/books/:id", handler.DeleteBook)
@@ -204,6 +372,25 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 	assert.Equal(t, 204, w.Code)
 }
 
+func TestBookHandler_DeleteBook_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	bookID := uuid.New()
+	mockService.On("DeleteBook", mock.Anything, bookID).Return(apperr.NotFound("book not found"))
+
+	r := gin.Default()
+	r.DELETE("/books/:id", handler.DeleteBook)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/books/"+bookID.String(), nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
+
 func TestBookHandler_GetBook(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -214,7 +401,7 @@ func TestBookHandler_GetBook(t *testing.T) {
 	book := models.Book{
 		ID:          bookID,
 		Title:       "Test Book",
-		ISBN:        "1234567890123",
+		ISBN:        "9780306406157",
 		Genre:       "Fiction",
 		Year:        2025,
 		Quantity:    10,
@@ -222,7 +409,7 @@ func TestBookHandler_GetBook(t *testing.T) {
 		PublisherID: uuid.New(),
 	}
 
-	mockService.On("GetBook", bookID).Return(&book, nil)
+	mockService.On("GetBook", mock.Anything, bookID).Return(&book, nil)
 
 	r := gin.Default()
 	r.GET("/books/:id", handler.GetBook)
@@ -250,18 +437,21 @@ func TestBookHandler_IssueBook(t *testing.T) {
 	book := &models.Book{
 		ID:             bookID,
 		Title:          "Test Book",
-		ISBN:           "1234567890123",
+		ISBN:           "9780306406157",
 		Quantity:       2,
 		QuantityIssued: 1,
 	}
 
-	mockService.On("IssueBook", bookID).Return(book, nil)
+	memberID := uuid.New()
+	mockService.On("IssueBook", mock.Anything, bookID, memberID).Return(book, nil)
 
 	r := gin.Default()
 	r.POST("/books/:id/issue", handler.IssueBook)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/books/"+bookID.String()+"/issue", nil)
+	reqBody, _ := json.Marshal(api.IssueBookRequest{MemberID: memberID})
+	req, _ := http.NewRequest("POST", "/books/"+bookID.String()+"/issue", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, 200, w.Code)
@@ -283,18 +473,21 @@ func TestBookHandler_ReturnBook(t *testing.T) {
 	book := &models.Book{
 		ID:             bookID,
 		Title:          "Test Book",
-		ISBN:           "1234567890123",
+		ISBN:           "9780306406157",
 		Quantity:       2,
 		QuantityIssued: 0,
 	}
 
-	mockService.On("ReturnBook", bookID).Return(book, nil)
+	memberID := uuid.New()
+	mockService.On("ReturnBook", mock.Anything, bookID, memberID).Return(book, nil)
 
 	r := gin.Default()
 	r.POST("/books/:id/return", handler.ReturnBook)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/books/"+bookID.String()+"/return", nil)
+	reqBody, _ := json.Marshal(api.ReturnBookRequest{MemberID: memberID})
+	req, _ := http.NewRequest("POST", "/books/"+bookID.String()+"/return", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, 200, w.Code)
@@ -305,3 +498,223 @@ func TestBookHandler_ReturnBook(t *testing.T) {
 	assert.Equal(t, 0, response.QuantityIssued)
 	assert.Equal(t, book.Title, response.Title)
 }
+
+func TestBookHandler_IssueBook_MissingMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	r := gin.Default()
+	r.POST("/books/:id/issue", handler.IssueBook)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/books/"+uuid.New().String()+"/issue", bytes.NewBufferString("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	mockService.AssertNotCalled(t, "IssueBook", mock.Anything, mock.Anything)
+}
+
+func TestBookHandler_RenewBook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	bookID := uuid.New()
+	memberID := uuid.New()
+	loan := &models.Loan{BookID: bookID, MemberID: memberID, DueAt: time.Now().Add(28 * 24 * time.Hour), Renewals: 1}
+	mockService.On("RenewBook", mock.Anything, bookID, memberID).Return(loan, nil)
+
+	r := gin.Default()
+	r.POST("/books/:id/renew", handler.RenewBook)
+
+	reqBody, _ := json.Marshal(api.RenewBookRequest{MemberID: memberID})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/books/"+bookID.String()+"/renew", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Loan
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Renewals)
+}
+
+func TestBookHandler_RenewBook_Refused(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	bookID := uuid.New()
+	memberID := uuid.New()
+	mockService.On("RenewBook", mock.Anything, bookID, memberID).Return(nil, fmt.Errorf("failed to renew book: %w", apperr.Conflict("book has pending holds")))
+
+	r := gin.Default()
+	r.POST("/books/:id/renew", handler.RenewBook)
+
+	reqBody, _ := json.Marshal(api.RenewBookRequest{MemberID: memberID})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/books/"+bookID.String()+"/renew", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestBookHandler_ErrorStatuses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{"not found", apperr.NotFound("book not found"), 404, "book not found"},
+		{"wrapped conflict", fmt.Errorf("failed to issue book: %w", apperr.Conflict("no available copies to issue")), 409, "failed to issue book: no available copies to issue"},
+		{"validation", apperr.Validation("book refers to a missing author"), 400, "book refers to a missing author"},
+		{"unavailable", apperr.Wrap(apperr.ErrUnavailable, errors.New("dial tcp: connection refused"), "database unavailable"), 503, "database unavailable"},
+		{"untyped", errors.New("pq: something unexpected"), 500, "An unexpected error occurred"},
+	}
+
+	for _, tc := range cases {
+		mockService := new(MockBookService)
+		handler := api.NewBookHandler(mockService)
+
+		bookID := uuid.New()
+		mockService.On("GetBook", mock.Anything, bookID).Return(nil, tc.err)
+
+		r := gin.New()
+		r.GET("/books/:id", handler.GetBook)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books/"+bookID.String(), nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.status, w.Code, tc.name)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"), tc.name)
+		var body problem.Details
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), tc.name)
+		assert.Equal(t, tc.status, body.Status, tc.name)
+		assert.Equal(t, tc.message, body.Detail, tc.name)
+		assert.Equal(t, "/books/"+bookID.String(), body.Instance, tc.name)
+	}
+}
+
+func TestBookHandler_ClientCanceled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bookID := uuid.New()
+	mockService.On("GetBook", mock.Anything, bookID).Run(func(args mock.Arguments) {
+		// The handler passes on the request's context
+		assert.Equal(t, ctx, args.Get(0))
+		cancel()
+	}).Return(nil, apperr.Wrap(apperr.ErrUnavailable, context.Canceled, "database unavailable"))
+
+	r := gin.New()
+	r.GET("/books/:id", handler.GetBook)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/books/"+bookID.String(), nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, api.StatusClientClosedRequest, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestBookHandler_CreateBook_ValidationProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	r := gin.New()
+	r.POST("/books", handler.CreateBook)
+	r.GET("/books", handler.ListBooks)
+
+	send := func(method, target, body string) (*httptest.ResponseRecorder, problem.Details) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		var details problem.Details
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+		return w, details
+	}
+
+	w, details := send("POST", "/books", `{"isbn": "123", "year": 5, "quantity": 1}`)
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, problem.TypeValidation, details.Type)
+	assert.Equal(t, "/books", details.Instance)
+	assert.Contains(t, details.Errors, problem.FieldError{Field: "title", Message: "is required"})
+	assert.Contains(t, details.Errors, problem.FieldError{Field: "isbn", Message: "must be a valid ISBN-10 or ISBN-13"})
+	assert.Contains(t, details.Errors, problem.FieldError{Field: "year", Message: "must be at least 1000"})
+	assert.NotContains(t, w.Body.String(), "CreateBookRequest", "validator internals must not leak")
+
+	w, details = send("POST", "/books", `{"quantity": "ten"}`)
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, []problem.FieldError{{Field: "quantity", Message: "must be an integer"}}, details.Errors)
+
+	w, details = send("POST", "/books", `{"title": `)
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, "Request body is not valid JSON", details.Detail)
+
+	w, details = send("GET", "/books?limit=500&sort=isbn", "")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, details.Errors, problem.FieldError{Field: "limit", Message: "must be at most 100"})
+	assert.Contains(t, details.Errors, problem.FieldError{Field: "sort", Message: "must be one of: title, year, created_at"})
+
+	mockService.AssertNotCalled(t, "CreateBook", mock.Anything)
+}
+
+func TestBookHandler_ISBN(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBookService)
+	handler := api.NewBookHandler(mockService)
+
+	book := models.Book{ID: uuid.New(), Title: "Test Book", ISBN: "9780306406157"}
+	mockService.On("GetBookByISBN", mock.Anything, "0-306-40615-2").Return(&book, nil)
+	mockService.On("CreateBook", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil)
+
+	r := gin.New()
+	r.GET("/books/:id", handler.GetBook)
+	r.GET("/books/isbn/:isbn", handler.GetBookByISBN)
+	r.POST("/books", handler.CreateBook)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books/isbn/0-306-40615-2", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), book.ID.String())
+
+	// Hyphenated ISBN-10 input is accepted; a bad check digit is not
+	for isbn, status := range map[string]int{"0-306-40615-2": 201, "978-0-306-40615-7": 201, "9780306406158": 400} {
+		body, _ := json.Marshal(api.CreateBookRequest{
+			Title:       "Test Book",
+			ISBN:        isbn,
+			AuthorID:    uuid.New(),
+			PublisherID: uuid.New(),
+			Year:        2025,
+			Genre:       "Fiction",
+			Quantity:    1,
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code, isbn)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+//go:generate go run ../../cmd/openapi
+
+//go:embed docs/openapi.json
+var openAPISpec []byte
+
+// OpenAPISpec returns the OpenAPI document generated from the handler
+// annotations
+func OpenAPISpec() []byte {
+	return openAPISpec
+}
+
+// DocsHandler serves the OpenAPI document and a Swagger UI for it. The UI's
+// assets are bundled in the binary.
+type DocsHandler struct {
+	ui gin.HandlerFunc
+}
+
+func NewDocsHandler() *DocsHandler {
+	// Relative to /docs/index.html, so the UI also works behind a path prefix
+	return &DocsHandler{ui: ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("../openapi.json"))}
+}
+
+// Spec godoc
+// @Summary OpenAPI document
+// @Description the OpenAPI 3 description of this API
+// @Tags operations
+// @Produce  json
+// @Success 200 {object} map[string]interface{}
+// @Router /openapi.json [get]
+func (h *DocsHandler) Spec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openAPISpec)
+}
+
+// Index godoc
+// @Summary API documentation
+// @Description redirect to the Swagger UI page
+// @Tags operations
+// @Success 301 "Moved Permanently"
+// @Router /docs [get]
+func (h *DocsHandler) Index(c *gin.Context) {
+	// Relative to /docs, so the redirect also works behind a path prefix.
+	// http.Redirect would make the path absolute, so the header is set as is.
+	c.Header("Location", "docs/index.html")
+	c.Status(http.StatusMovedPermanently)
+}
+
+// UI godoc
+// @Summary Swagger UI
+// @Description the Swagger UI page rendering the OpenAPI document, and its assets
+// @Tags operations
+// @Produce  html
+// @Param any path string true "index.html or a Swagger UI asset"
+// @Success 200 {string} string "HTML page or asset"
+// @Failure 404 {string} string "Not Found"
+// @Router /docs/{any} [get]
+func (h *DocsHandler) UI(c *gin.Context) {
+	h.ui(c)
+}
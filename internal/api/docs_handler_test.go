@@ -0,0 +1,58 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := api.NewDocsHandler()
+	r := gin.New()
+	r.GET("/openapi.json", handler.Spec)
+	r.GET("/docs", handler.Index)
+	r.GET("/docs/*any", handler.UI)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/docs", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "docs/index.html", w.Header().Get("Location"), "relative, so a path prefix is kept")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/docs/index.html", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "openapi.json", "the UI loads the generated document")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/docs/swagger-ui-bundle.js", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "assets are bundled")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, api.OpenAPISpec(), w.Body.Bytes())
+}
+
+func TestDocsHandler_IndexBehindPathPrefix(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := api.NewDocsHandler()
+	r := gin.New()
+	r.GET("/library/docs", handler.Index)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/library/docs", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+
+	base, _ := url.Parse("https://example.com/library/docs")
+	location, err := url.Parse(w.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/library/docs/index.html", base.ResolveReference(location).String())
+}
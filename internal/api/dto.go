@@ -1,10 +1,15 @@
 package api
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+)
 
 type CreateBookRequest struct {
 	Title       string    `json:"title" validate:"required"`
-	ISBN        string    `json:"isbn" validate:"required,len=13"`
+	ISBN        string    `json:"isbn" validate:"required,isbn"`
 	AuthorID    uuid.UUID `json:"author_id" validate:"required"`
 	PublisherID uuid.UUID `json:"publisher_id" validate:"required"`
 	Year        int       `json:"year" validate:"required,min=1000,max=9999"`
@@ -12,12 +17,160 @@ type CreateBookRequest struct {
 	Quantity    int       `json:"quantity" validate:"required,min=0"`
 }
 
+type ListBooksQuery struct {
+	// Page is capped so the offset stays small; use cursor to go deeper
+	Page        int    `form:"page,default=1" validate:"min=1,max=10000"`
+	Limit       int    `form:"limit,default=10" validate:"min=1,max=100"`
+	Genre       string `form:"genre" validate:"max=100"`
+	YearFrom    int    `form:"year_from" validate:"omitempty,min=1000,max=9999"`
+	YearTo      int    `form:"year_to" validate:"omitempty,min=1000,max=9999,gtefield=YearFrom"`
+	AuthorID    string `form:"author_id" validate:"omitempty,uuid"`
+	PublisherID string `form:"publisher_id" validate:"omitempty,uuid"`
+	Available   *bool  `form:"available"`
+	Q           string `form:"q" validate:"max=255"`
+	Sort        string `form:"sort" validate:"omitempty,oneof=title year created_at"`
+	Order       string `form:"order" validate:"omitempty,oneof=asc desc"`
+	// Cursor switches to keyset pagination; pass it empty for the first page
+	Cursor       string `form:"cursor" validate:"max=1024"`
+	IncludeTotal bool   `form:"include_total"`
+}
+
+type SearchQuery struct {
+	Q     string `form:"q" validate:"required,max=255"`
+	Page  int    `form:"page,default=1" validate:"min=1,max=10000"`
+	Limit int    `form:"limit,default=10" validate:"min=1,max=100"`
+}
+
 type UpdateBookRequest struct {
 	Title       string    `json:"title" validate:"required"`
-	ISBN        string    `json:"isbn" validate:"required,len=13"`
+	ISBN        string    `json:"isbn" validate:"required,isbn"`
 	AuthorID    uuid.UUID `json:"author_id" validate:"required"`
 	PublisherID uuid.UUID `json:"publisher_id" validate:"required"`
 	Year        int       `json:"year" validate:"required,min=1000,max=9999"`
 	Genre       string    `json:"genre" validate:"required"`
 	Quantity    int       `json:"quantity" validate:"required,min=0"`
 }
+
+type CreateAuthorRequest struct {
+	Name      string `json:"name" validate:"required"`
+	Biography string `json:"biography"`
+}
+
+type UpdateAuthorRequest struct {
+	Name      string `json:"name" validate:"required"`
+	Biography string `json:"biography"`
+}
+
+type CreatePublisherRequest struct {
+	Name     string     `json:"name" validate:"required"`
+	Location string     `json:"location"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type UpdatePublisherRequest struct {
+	Name     string     `json:"name" validate:"required"`
+	Location string     `json:"location"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type CreateMemberRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+	Phone string `json:"phone"`
+	// Subject is the sub claim of the member's bearer tokens, if they sign in
+	Subject string `json:"subject" validate:"max=255"`
+}
+
+type UpdateMemberRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+	Phone string `json:"phone"`
+	// Subject is the sub claim of the member's bearer tokens; empty unlinks
+	// the member from any account
+	Subject string `json:"subject" validate:"max=255"`
+}
+
+type IssueBookRequest struct {
+	MemberID uuid.UUID `json:"member_id" validate:"required"`
+}
+
+type ReturnBookRequest struct {
+	MemberID uuid.UUID `json:"member_id" validate:"required"`
+}
+
+type RenewBookRequest struct {
+	MemberID uuid.UUID `json:"member_id" validate:"required"`
+}
+
+type PlaceHoldRequest struct {
+	// MemberID is required of staff; members place holds for themselves
+	MemberID uuid.UUID `json:"member_id"`
+}
+
+type PayFineRequest struct {
+	AmountCents int64  `json:"amount_cents" validate:"required,min=1"`
+	Note        string `json:"note"`
+}
+
+type WaiveFineRequest struct {
+	// AmountCents of zero waives the whole outstanding balance
+	AmountCents int64  `json:"amount_cents" validate:"min=0"`
+	Reason      string `json:"reason" validate:"required"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,api_key_scope"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateAPIKeyResponse is the only time the plaintext key is returned
+type CreateAPIKeyResponse struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+// The envelopes below document the shape of list responses; the handlers
+// build the same JSON with gin.H. Annotations narrow data to the element
+// type, e.g. PageResponse{data=[]models.Book}.
+
+// PageResponse is one page of an offset-paginated listing
+type PageResponse struct {
+	Data  interface{} `json:"data"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+}
+
+// CursorPageResponse is one page of a keyset-paginated listing. A null
+// cursor means there is no page in that direction.
+type CursorPageResponse struct {
+	Data       interface{} `json:"data"`
+	Limit      int         `json:"limit"`
+	NextCursor *string     `json:"next_cursor"`
+	PrevCursor *string     `json:"prev_cursor"`
+	// Total is only counted when include_total is set
+	Total *int64 `json:"total,omitempty"`
+}
+
+// ListResponse is a complete, unpaginated listing
+type ListResponse struct {
+	Data interface{} `json:"data"`
+}
+
+// FineListResponse lists a member's fines with what they still owe
+type FineListResponse struct {
+	Data         interface{} `json:"data"`
+	BalanceCents int64       `json:"balance_cents"`
+}
+
+// HealthResponse is returned by the liveness and readiness probes
+type HealthResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+}
+
+type ComponentHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/isbn"
+	"github.com/library-api/internal/logging"
+	"github.com/library-api/internal/problem"
+)
+
+// newValidator returns a validator that reports fields by their JSON or
+// query parameter name rather than the Go field name
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	// Replaces the built-in isbn rule, which allows only a few separators
+	validate.RegisterValidation("isbn", func(fl validator.FieldLevel) bool {
+		return isbn.Valid(fl.Field().String())
+	})
+	return validate
+}
+
+// errorStatus maps an error's kind to its HTTP status
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperr.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperr.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperr.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// StatusClientClosedRequest is recorded, following nginx, for requests the
+// client abandoned before a response was ready
+const StatusClientClosedRequest = 499
+
+// respondError writes the response for an error returned by a service.
+// Server-side failures are logged with their cause, which clients never see.
+func respondError(c *gin.Context, err error) {
+	// A client that went away cancels its queries; that is not a failure
+	if ctx := c.Request.Context(); ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		logging.FromContext(ctx).InfoContext(ctx, "request canceled by client",
+			"method", c.Request.Method, "path", c.Request.URL.Path)
+		c.AbortWithStatus(StatusClientClosedRequest)
+		return
+	}
+
+	status := errorStatus(err)
+	detail := err.Error()
+
+	if status >= http.StatusInternalServerError {
+		cause := err
+		var appErr *apperr.Error
+		if errors.As(err, &appErr) && appErr.Unwrap() != nil {
+			cause = appErr.Unwrap()
+		}
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).ErrorContext(ctx, "request failed",
+			"method", c.Request.Method, "path", c.Request.URL.Path, "status", status, "error", cause.Error())
+	}
+	if status == http.StatusInternalServerError {
+		detail = "An unexpected error occurred"
+	}
+
+	problem.Write(c, problem.New(status, detail))
+}
+
+// respondProblem writes a problem with the given status and detail
+func respondProblem(c *gin.Context, status int, detail string) {
+	problem.Write(c, problem.New(status, detail))
+}
+
+// respondInvalid writes a 400 for a request that failed to bind or validate,
+// naming each offending field without exposing validator internals
+func respondInvalid(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]problem.FieldError, len(validationErrs))
+		for i, fe := range validationErrs {
+			fields[i] = problem.FieldError{Field: fieldPath(fe), Message: fieldMessage(fe)}
+		}
+		problem.Write(c, problem.Validation("One or more fields are invalid", fields))
+	case errors.As(err, &typeErr):
+		field := problem.FieldError{Field: typeErr.Field, Message: "must be " + jsonTypeName(typeErr.Type)}
+		problem.Write(c, problem.Validation("One or more fields are invalid", []problem.FieldError{field}))
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		respondProblem(c, http.StatusBadRequest, "Request body is not valid JSON")
+	case errors.Is(err, io.EOF):
+		respondProblem(c, http.StatusBadRequest, "Request body is empty")
+	default:
+		respondProblem(c, http.StatusBadRequest, "Request could not be parsed")
+	}
+}
+
+// fieldPath is the field's JSON path without the struct name, e.g. scopes[1]
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+// fieldMessage describes a failed validation rule in plain words
+func fieldMessage(fe validator.FieldError) string {
+	isString := fe.Kind() == reflect.String
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "len":
+		if isString {
+			return fmt.Sprintf("must be exactly %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must contain exactly %s items", fe.Param())
+	case "min", "gte":
+		if isString {
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max", "lte":
+		if isString {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "gtefield":
+		return "must not be less than " + toSnake(fe.Param())
+	case "email":
+		return "must be a valid email address"
+	case "uuid":
+		return "must be a valid UUID"
+	case "isbn":
+		return "must be a valid ISBN-10 or ISBN-13"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "api_key_scope":
+		return "is not a scope API keys can be granted"
+	default:
+		return "is invalid"
+	}
+}
+
+// jsonTypeName names a Go type the way a JSON client would think of it
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// toSnake turns a Go field name such as YearFrom into year_from
+func toSnake(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/service"
+)
+
+type FineHandler struct {
+	fineService service.FineService
+	validate    *validator.Validate
+}
+
+func NewFineHandler(fineService service.FineService) *FineHandler {
+	return &FineHandler{
+		fineService: fineService,
+		validate:    newValidator(),
+	}
+}
+
+// ListMemberFines godoc
+// @Summary List a member's fines
+// @Description get every fine of a member and their total outstanding balance in cents
+// @Tags fines
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Member ID"
+// @Success 200 {object} FineListResponse{data=[]models.Fine}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/members/{id}/fines [get]
+func (h *FineHandler) ListMemberFines(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid member ID")
+		return
+	}
+
+	fines, balance, err := h.fineService.ListMemberFines(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":          fines,
+		"balance_cents": balance,
+	})
+}
+
+// GetFine godoc
+// @Summary Get a fine
+// @Description get fine by ID with its ledger entries
+// @Tags fines
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Fine ID"
+// @Success 200 {object} models.Fine
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/fines/{id} [get]
+func (h *FineHandler) GetFine(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid fine ID")
+		return
+	}
+
+	fine, err := h.fineService.GetFine(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fine)
+}
+
+// PayFine godoc
+// @Summary Pay a fine
+// @Description record a payment against a fine's outstanding balance
+// @Tags fines
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Fine ID"
+// @Param payment body PayFineRequest true "Payment"
+// @Success 200 {object} models.Fine
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/fines/{id}/pay [post]
+func (h *FineHandler) PayFine(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid fine ID")
+		return
+	}
+
+	var req PayFineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	fine, err := h.fineService.PayFine(c.Request.Context(), id, req.AmountCents, req.Note)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fine)
+}
+
+// WaiveFine godoc
+// @Summary Waive a fine
+// @Description forgive part or, with amount_cents omitted, all of a fine's outstanding balance
+// @Tags fines
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Fine ID"
+// @Param waiver body WaiveFineRequest true "Waiver"
+// @Success 200 {object} models.Fine
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/fines/{id}/waive [post]
+func (h *FineHandler) WaiveFine(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid fine ID")
+		return
+	}
+
+	var req WaiveFineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	fine, err := h.fineService.WaiveFine(c.Request.Context(), id, req.AmountCents, req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fine)
+}
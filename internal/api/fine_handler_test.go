@@ -0,0 +1,149 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFineService struct {
+	mock.Mock
+}
+
+func (m *MockFineService) GetFine(ctx context.Context, id uuid.UUID) (*models.Fine, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Fine), args.Error(1)
+}
+
+func (m *MockFineService) ListMemberFines(ctx context.Context, memberID uuid.UUID) ([]models.Fine, int64, error) {
+	args := m.Called(ctx, memberID)
+	return args.Get(0).([]models.Fine), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockFineService) PayFine(ctx context.Context, id uuid.UUID, amountCents int64, note string) (*models.Fine, error) {
+	args := m.Called(ctx, id, amountCents, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Fine), args.Error(1)
+}
+
+func (m *MockFineService) WaiveFine(ctx context.Context, id uuid.UUID, amountCents int64, reason string) (*models.Fine, error) {
+	args := m.Called(ctx, id, amountCents, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Fine), args.Error(1)
+}
+
+func (m *MockFineService) AccrueFines(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func TestFineHandler_ListMemberFines(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockFineService)
+	handler := api.NewFineHandler(mockService)
+
+	memberID := uuid.New()
+	fines := []models.Fine{{MemberID: memberID, AmountCents: 300, BalanceCents: 300}}
+	mockService.On("ListMemberFines", mock.Anything, memberID).Return(fines, int64(300), nil)
+
+	r := gin.Default()
+	r.GET("/members/:id/fines", handler.ListMemberFines)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/members/"+memberID.String()+"/fines", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(300), response["balance_cents"])
+}
+
+func TestFineHandler_PayFine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockFineService)
+	handler := api.NewFineHandler(mockService)
+
+	fineID := uuid.New()
+	mockService.On("PayFine", mock.Anything, fineID, int64(250), "cash").
+		Return(&models.Fine{ID: fineID, AmountCents: 500, PaidCents: 250, BalanceCents: 250}, nil)
+
+	r := gin.Default()
+	r.POST("/fines/:id/pay", handler.PayFine)
+
+	body, _ := json.Marshal(api.PayFineRequest{AmountCents: 250, Note: "cash"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/fines/"+fineID.String()+"/pay", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Fine
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(250), response.BalanceCents)
+}
+
+func TestFineHandler_PayFine_RequiresAmount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockFineService)
+	handler := api.NewFineHandler(mockService)
+
+	r := gin.Default()
+	r.POST("/fines/:id/pay", handler.PayFine)
+
+	body, _ := json.Marshal(api.PayFineRequest{Note: "nothing"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/fines/"+uuid.New().String()+"/pay", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	mockService.AssertNotCalled(t, "PayFine", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFineHandler_WaiveFine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockFineService)
+	handler := api.NewFineHandler(mockService)
+
+	fineID := uuid.New()
+	mockService.On("WaiveFine", mock.Anything, fineID, int64(0), "first offence").
+		Return(&models.Fine{ID: fineID, AmountCents: 500, WaivedCents: 500}, nil)
+
+	r := gin.Default()
+	r.POST("/fines/:id/waive", handler.WaiveFine)
+
+	body, _ := json.Marshal(api.WaiveFineRequest{Reason: "first offence"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/fines/"+fineID.String()+"/waive", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	mockService.AssertExpectations(t)
+}
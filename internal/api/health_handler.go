@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Health statuses reported by the probes
+const (
+	HealthOK           = "ok"
+	HealthUnavailable  = "unavailable"
+	HealthShuttingDown = "shutting_down"
+)
+
+// HealthCheck reports whether a dependency is usable by returning nil
+type HealthCheck func(ctx context.Context) error
+
+type HealthHandler struct {
+	checks       map[string]HealthCheck
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewHealthHandler returns a handler whose readiness probe runs checks,
+// keyed by component name, each bounded by timeout
+func NewHealthHandler(checks map[string]HealthCheck, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// SetShuttingDown makes the readiness probe fail from now on, so the
+// orchestrator stops routing traffic to this instance while it drains
+func (h *HealthHandler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description reports that the process is up; it does not check dependencies
+// @Tags health
+// @Produce  json
+// @Success 200 {object} HealthResponse
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: HealthOK})
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description reports whether the database is reachable and its schema is up to date
+// @Tags health
+// @Produce  json
+// @Success 200 {object} HealthResponse
+// @Failure 503 {object} HealthResponse
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	if h.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, HealthResponse{Status: HealthShuttingDown})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	// Checks run concurrently so one slow dependency does not hide the others
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		components = make(map[string]ComponentHealth, len(h.checks))
+	)
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+			result := ComponentHealth{Status: HealthOK}
+			if err := check(ctx); err != nil {
+				result = ComponentHealth{Status: HealthUnavailable, Error: err.Error()}
+			}
+			mu.Lock()
+			components[name] = result
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	resp := HealthResponse{Status: HealthOK, Components: components}
+	for _, component := range components {
+		if component.Status != HealthOK {
+			resp.Status = HealthUnavailable
+		}
+	}
+
+	status := http.StatusOK
+	if resp.Status != HealthOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, resp)
+}
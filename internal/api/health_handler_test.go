@@ -0,0 +1,86 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func probe(r *gin.Engine, path string) (int, api.HealthResponse) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	r.ServeHTTP(w, req)
+
+	var response api.HealthResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w.Code, response
+}
+
+func TestHealthHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var dbErr error
+	handler := api.NewHealthHandler(map[string]api.HealthCheck{
+		"database":   func(ctx context.Context) error { return dbErr },
+		"migrations": func(ctx context.Context) error { return nil },
+	}, time.Second)
+
+	r := gin.Default()
+	r.GET("/healthz", handler.Liveness)
+	r.GET("/readyz", handler.Readiness)
+
+	code, response := probe(r, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, api.HealthOK, response.Status)
+	assert.Equal(t, map[string]api.ComponentHealth{
+		"database":   {Status: api.HealthOK},
+		"migrations": {Status: api.HealthOK},
+	}, response.Components)
+
+	dbErr = errors.New("connection refused")
+	code, response = probe(r, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, api.HealthUnavailable, response.Status)
+	assert.Equal(t, api.ComponentHealth{Status: api.HealthUnavailable, Error: "connection refused"}, response.Components["database"])
+	assert.Equal(t, api.HealthOK, response.Components["migrations"].Status)
+
+	// Liveness ignores dependencies
+	code, response = probe(r, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, api.HealthOK, response.Status)
+
+	dbErr = nil
+	handler.SetShuttingDown()
+	code, response = probe(r, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, api.HealthShuttingDown, response.Status)
+
+	code, _ = probe(r, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestHealthHandler_CheckTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := api.NewHealthHandler(map[string]api.HealthCheck{
+		"database": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}, 20*time.Millisecond)
+
+	r := gin.Default()
+	r.GET("/readyz", handler.Readiness)
+
+	code, response := probe(r, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), response.Components["database"].Error)
+}
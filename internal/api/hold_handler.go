@@ -0,0 +1,240 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/auth"
+	"github.com/library-api/internal/problem"
+	"github.com/library-api/internal/service"
+)
+
+type HoldHandler struct {
+	holdService   service.HoldService
+	memberService service.MemberService
+	validate      *validator.Validate
+}
+
+func NewHoldHandler(holdService service.HoldService, memberService service.MemberService) *HoldHandler {
+	return &HoldHandler{
+		holdService:   holdService,
+		memberService: memberService,
+		validate:      newValidator(),
+	}
+}
+
+// managesHolds reports whether the caller may act on any member's holds.
+// Other callers with holds:write only act on their own.
+func managesHolds(c *gin.Context) bool {
+	principal, ok := auth.CurrentPrincipal(c)
+	return ok && principal.Can(auth.PermHoldsManage)
+}
+
+// PlaceHold godoc
+// @Summary Place a hold on a book
+// @Description queue a member for the next available copy of a book with none on the shelf. Members always place holds for themselves and may omit member_id.
+// @Tags holds
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Book ID"
+// @Param hold body PlaceHoldRequest false "Member placing the hold"
+// @Success 201 {object} models.Hold
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/{id}/holds [post]
+func (h *HoldHandler) PlaceHold(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	// Members may send no body at all
+	var req PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	memberID := req.MemberID
+	if managesHolds(c) {
+		if memberID == uuid.Nil {
+			problem.Write(c, problem.Validation("One or more fields are invalid",
+				[]problem.FieldError{{Field: "member_id", Message: "is required"}}))
+			return
+		}
+	} else {
+		member, ok := currentMember(c, h.memberService)
+		if !ok {
+			return
+		}
+		if memberID != uuid.Nil && memberID != member.ID {
+			respondProblem(c, http.StatusForbidden, "Members can only place holds for themselves")
+			return
+		}
+		memberID = member.ID
+	}
+
+	hold, err := h.holdService.PlaceHold(c.Request.Context(), id, memberID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, hold)
+}
+
+// ListBookHolds godoc
+// @Summary List a book's hold queue
+// @Description get the waiting and ready holds of a book in the order they are served
+// @Tags holds
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Book ID"
+// @Success 200 {object} ListResponse{data=[]models.Hold}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/{id}/holds [get]
+func (h *HoldHandler) ListBookHolds(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	holds, err := h.holdService.ListBookHolds(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": holds})
+}
+
+// ListMemberHolds godoc
+// @Summary List a member's holds
+// @Description get every hold placed by a member, newest first
+// @Tags holds
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Member ID"
+// @Success 200 {object} ListResponse{data=[]models.Hold}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/members/{id}/holds [get]
+func (h *HoldHandler) ListMemberHolds(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid member ID")
+		return
+	}
+
+	holds, err := h.holdService.ListMemberHolds(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": holds})
+}
+
+// GetHold godoc
+// @Summary Get a hold
+// @Description get hold by ID
+// @Tags holds
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Hold ID"
+// @Success 200 {object} models.Hold
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/holds/{id} [get]
+func (h *HoldHandler) GetHold(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid hold ID")
+		return
+	}
+
+	hold, err := h.holdService.GetHold(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// CancelHold godoc
+// @Summary Cancel a hold
+// @Description cancel a waiting or ready hold; a copy set aside for it passes to the next member. Members can only cancel their own holds.
+// @Tags holds
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Hold ID"
+// @Success 200 {object} models.Hold
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/holds/{id}/cancel [post]
+func (h *HoldHandler) CancelHold(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid hold ID")
+		return
+	}
+
+	if !managesHolds(c) {
+		member, ok := currentMember(c, h.memberService)
+		if !ok {
+			return
+		}
+		hold, err := h.holdService.GetHold(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if hold.MemberID != member.ID {
+			respondProblem(c, http.StatusForbidden, "Members can only cancel their own holds")
+			return
+		}
+	}
+
+	hold, err := h.holdService.CancelHold(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
@@ -0,0 +1,218 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/auth"
+	"github.com/library-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHoldService struct {
+	mock.Mock
+}
+
+func (m *MockHoldService) PlaceHold(ctx context.Context, bookID, memberID uuid.UUID) (*models.Hold, error) {
+	args := m.Called(ctx, bookID, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Hold), args.Error(1)
+}
+
+func (m *MockHoldService) CancelHold(ctx context.Context, id uuid.UUID) (*models.Hold, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Hold), args.Error(1)
+}
+
+func (m *MockHoldService) GetHold(ctx context.Context, id uuid.UUID) (*models.Hold, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Hold), args.Error(1)
+}
+
+func (m *MockHoldService) ListBookHolds(ctx context.Context, bookID uuid.UUID) ([]models.Hold, error) {
+	args := m.Called(ctx, bookID)
+	return args.Get(0).([]models.Hold), args.Error(1)
+}
+
+func (m *MockHoldService) ListMemberHolds(ctx context.Context, memberID uuid.UUID) ([]models.Hold, error) {
+	args := m.Called(ctx, memberID)
+	return args.Get(0).([]models.Hold), args.Error(1)
+}
+
+func (m *MockHoldService) ProcessHolds(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+// asPrincipal authenticates every request as subject with roles
+func asPrincipal(subject string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := &auth.Principal{Subject: subject, Roles: roles}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+func TestHoldHandler_PlaceHold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockHoldService)
+	handler := api.NewHoldHandler(mockService, new(MockMemberService))
+
+	bookID := uuid.New()
+	memberID := uuid.New()
+	hold := &models.Hold{BookID: bookID, MemberID: memberID, Status: models.HoldStatusWaiting}
+	mockService.On("PlaceHold", mock.Anything, bookID, memberID).Return(hold, nil)
+
+	r := gin.Default()
+	r.Use(asPrincipal("librarian-1", auth.RoleLibrarian))
+	r.POST("/books/:id/holds", handler.PlaceHold)
+
+	body, _ := json.Marshal(api.PlaceHoldRequest{MemberID: memberID})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/books/"+bookID.String()+"/holds", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+
+	var response models.Hold
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldStatusWaiting, response.Status)
+}
+
+func TestHoldHandler_PlaceHold_CopiesAvailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockHoldService)
+	handler := api.NewHoldHandler(mockService, new(MockMemberService))
+
+	bookID := uuid.New()
+	memberID := uuid.New()
+	mockService.On("PlaceHold", mock.Anything, bookID, memberID).
+		Return(nil, fmt.Errorf("failed to place hold: %w", apperr.Conflict("copies are available, issue the book instead")))
+
+	r := gin.Default()
+	r.Use(asPrincipal("librarian-1", auth.RoleLibrarian))
+	r.POST("/books/:id/holds", handler.PlaceHold)
+
+	body, _ := json.Marshal(api.PlaceHoldRequest{MemberID: memberID})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/books/"+bookID.String()+"/holds", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestHoldHandler_CancelHold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockHoldService)
+	handler := api.NewHoldHandler(mockService, new(MockMemberService))
+
+	holdID := uuid.New()
+	mockService.On("CancelHold", mock.Anything, holdID).Return(&models.Hold{ID: holdID, Status: models.HoldStatusCancelled}, nil)
+
+	r := gin.Default()
+	r.Use(asPrincipal("librarian-1", auth.RoleLibrarian))
+	r.POST("/holds/:id/cancel", handler.CancelHold)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/holds/"+holdID.String()+"/cancel", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Hold
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldStatusCancelled, response.Status)
+}
+
+func TestHoldHandler_MemberAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	bookID := uuid.New()
+	alice := &models.Member{ID: uuid.New(), Name: "alice"}
+	bob := &models.Member{ID: uuid.New(), Name: "bob"}
+	aliceHold := &models.Hold{ID: uuid.New(), BookID: bookID, MemberID: alice.ID, Status: models.HoldStatusWaiting}
+	bobHold := &models.Hold{ID: uuid.New(), BookID: bookID, MemberID: bob.ID, Status: models.HoldStatusWaiting}
+
+	tests := []struct {
+		name     string
+		subject  string
+		role     string
+		method   string
+		path     string
+		body     string
+		memberID uuid.UUID // whose hold is placed, when the request gets that far
+		want     int
+	}{
+		{"member places own hold without a body", "alice", auth.RoleMember, "POST", "/books/" + bookID.String() + "/holds", "", alice.ID, 201},
+		{"member places own hold by ID", "alice", auth.RoleMember, "POST", "/books/" + bookID.String() + "/holds", `{"member_id":"` + alice.ID.String() + `"}`, alice.ID, 201},
+		{"member places hold for another member", "alice", auth.RoleMember, "POST", "/books/" + bookID.String() + "/holds", `{"member_id":"` + bob.ID.String() + `"}`, uuid.Nil, 403},
+		{"unlinked member places hold", "mallory", auth.RoleMember, "POST", "/books/" + bookID.String() + "/holds", "", uuid.Nil, 403},
+		{"librarian places hold for a member", "librarian-1", auth.RoleLibrarian, "POST", "/books/" + bookID.String() + "/holds", `{"member_id":"` + bob.ID.String() + `"}`, bob.ID, 201},
+		{"librarian places hold without member", "librarian-1", auth.RoleLibrarian, "POST", "/books/" + bookID.String() + "/holds", "{}", uuid.Nil, 400},
+		{"member cancels own hold", "alice", auth.RoleMember, "POST", "/holds/" + aliceHold.ID.String() + "/cancel", "", uuid.Nil, 200},
+		{"member cancels another member's hold", "alice", auth.RoleMember, "POST", "/holds/" + bobHold.ID.String() + "/cancel", "", uuid.Nil, 403},
+		{"unlinked member cancels a hold", "mallory", auth.RoleMember, "POST", "/holds/" + aliceHold.ID.String() + "/cancel", "", uuid.Nil, 403},
+		{"librarian cancels a member's hold", "librarian-1", auth.RoleLibrarian, "POST", "/holds/" + bobHold.ID.String() + "/cancel", "", uuid.Nil, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holdService := new(MockHoldService)
+			memberService := new(MockMemberService)
+			handler := api.NewHoldHandler(holdService, memberService)
+
+			memberService.On("GetMemberBySubject", mock.Anything, "alice").Return(alice, nil)
+			memberService.On("GetMemberBySubject", mock.Anything, "mallory").Return(nil, apperr.NotFound("member not found"))
+			holdService.On("PlaceHold", mock.Anything, bookID, mock.Anything).Return(aliceHold, nil)
+			for _, hold := range []*models.Hold{aliceHold, bobHold} {
+				holdService.On("GetHold", mock.Anything, hold.ID).Return(hold, nil)
+				holdService.On("CancelHold", mock.Anything, hold.ID).Return(hold, nil)
+			}
+
+			r := gin.Default()
+			r.Use(asPrincipal(tt.subject, tt.role))
+			r.POST("/books/:id/holds", handler.PlaceHold)
+			r.POST("/holds/:id/cancel", handler.CancelHold)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+			if tt.memberID != uuid.Nil {
+				holdService.AssertCalled(t, "PlaceHold", mock.Anything, bookID, tt.memberID)
+			} else {
+				holdService.AssertNotCalled(t, "PlaceHold", mock.Anything, mock.Anything)
+			}
+			if tt.want == 403 {
+				holdService.AssertNotCalled(t, "CancelHold", mock.Anything)
+			}
+		})
+	}
+}
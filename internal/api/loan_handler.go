@@ -0,0 +1,159 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/repository"
+	"github.com/library-api/internal/service"
+)
+
+type LoanHandler struct {
+	loanService service.LoanService
+}
+
+func NewLoanHandler(loanService service.LoanService) *LoanHandler {
+	return &LoanHandler{loanService: loanService}
+}
+
+// loanStatus reads the status query parameter, mapping "all" to no filter.
+// The second return value is false when the status is not recognised.
+func loanStatus(c *gin.Context) (string, bool) {
+	switch status := c.DefaultQuery("status", "all"); status {
+	case "all":
+		return "", true
+	case repository.LoanStatusActive, repository.LoanStatusReturned, repository.LoanStatusOverdue:
+		return status, true
+	default:
+		return "", false
+	}
+}
+
+// GetLoan godoc
+// @Summary Get a loan
+// @Description get loan by ID
+// @Tags loans
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Loan ID"
+// @Success 200 {object} models.Loan
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/loans/{id} [get]
+func (h *LoanHandler) GetLoan(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid loan ID")
+		return
+	}
+
+	loan, err := h.loanService.GetLoan(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, loan)
+}
+
+// ListBookLoans godoc
+// @Summary List a book's loans
+// @Description get active and historical loans of a book, newest first
+// @Tags loans
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Book ID"
+// @Param status query string false "active, returned, overdue or all (default)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success 200 {object} PageResponse{data=[]models.Loan}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/books/{id}/loans [get]
+func (h *LoanHandler) ListBookLoans(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	status, ok := loanStatus(c)
+	if !ok {
+		respondProblem(c, http.StatusBadRequest, "Invalid loan status")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	loans, total, err := h.loanService.ListBookLoans(c.Request.Context(), id, status, page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  loans,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ListMemberLoans godoc
+// @Summary List a member's loans
+// @Description get active and historical loans of a member, newest first
+// @Tags loans
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Member ID"
+// @Param status query string false "active, returned, overdue or all (default)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success 200 {object} PageResponse{data=[]models.Loan}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/members/{id}/loans [get]
+func (h *LoanHandler) ListMemberLoans(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid member ID")
+		return
+	}
+
+	status, ok := loanStatus(c)
+	if !ok {
+		respondProblem(c, http.StatusBadRequest, "Invalid loan status")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	loans, total, err := h.loanService.ListMemberLoans(c.Request.Context(), id, status, page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  loans,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
@@ -0,0 +1,105 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoanService struct {
+	mock.Mock
+}
+
+func (m *MockLoanService) GetLoan(ctx context.Context, id uuid.UUID) (*models.Loan, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Loan), args.Error(1)
+}
+
+func (m *MockLoanService) ListBookLoans(ctx context.Context, bookID uuid.UUID, status string, page, limit int) ([]models.Loan, int64, error) {
+	args := m.Called(ctx, bookID, status, page, limit)
+	return args.Get(0).([]models.Loan), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockLoanService) ListMemberLoans(ctx context.Context, memberID uuid.UUID, status string, page, limit int) ([]models.Loan, int64, error) {
+	args := m.Called(ctx, memberID, status, page, limit)
+	return args.Get(0).([]models.Loan), args.Get(1).(int64), args.Error(2)
+}
+
+func TestLoanHandler_ListBookLoans_Active(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockLoanService)
+	handler := api.NewLoanHandler(mockService)
+
+	bookID := uuid.New()
+	loans := []models.Loan{{BookID: bookID, MemberID: uuid.New(), IssuedAt: time.Now(), DueAt: time.Now().Add(time.Hour)}}
+	mockService.On("ListBookLoans", mock.Anything, bookID, "active", 1, 10).Return(loans, int64(1), nil)
+
+	r := gin.Default()
+	r.GET("/books/:id/loans", handler.ListBookLoans)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books/"+bookID.String()+"/loans?status=active", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response struct {
+		Data  []models.Loan `json:"data"`
+		Total int64         `json:"total"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), response.Total)
+	assert.Equal(t, bookID, response.Data[0].BookID)
+}
+
+func TestLoanHandler_ListMemberLoans_AllByDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockLoanService)
+	handler := api.NewLoanHandler(mockService)
+
+	memberID := uuid.New()
+	mockService.On("ListMemberLoans", mock.Anything, memberID, "", 1, 10).Return([]models.Loan{}, int64(0), nil)
+
+	r := gin.Default()
+	r.GET("/members/:id/loans", handler.ListMemberLoans)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/members/"+memberID.String()+"/loans", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestLoanHandler_ListMemberLoans_InvalidStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockLoanService)
+	handler := api.NewLoanHandler(mockService)
+
+	r := gin.Default()
+	r.GET("/members/:id/loans", handler.ListMemberLoans)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/members/"+uuid.New().String()+"/loans?status=lost", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	mockService.AssertNotCalled(t, "ListMemberLoans", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/service"
+)
+
+type MemberHandler struct {
+	memberService service.MemberService
+	validate      *validator.Validate
+}
+
+func NewMemberHandler(memberService service.MemberService) *MemberHandler {
+	return &MemberHandler{
+		memberService: memberService,
+		validate:      newValidator(),
+	}
+}
+
+// ListMembers godoc
+// @Summary List all members
+// @Description get members
+// @Tags members
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success 200 {object} PageResponse{data=[]models.Member}
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/members [get]
+func (h *MemberHandler) ListMembers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	members, total, err := h.memberService.ListMembers(c.Request.Context(), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  members,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetMember godoc
+// @Summary Get a member
+// @Description get member by ID
+// @Tags members
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Member ID"
+// @Success 200 {object} models.Member
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/members/{id} [get]
+func (h *MemberHandler) GetMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid member ID")
+		return
+	}
+
+	member, err := h.memberService.GetMember(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// CreateMember godoc
+// @Summary Create a member
+// @Description create new member
+// @Tags members
+// @Accept  json
+// @Produce  json
+// @Param member body CreateMemberRequest true "Create member"
+// @Success 201 {object} models.Member
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/members [post]
+func (h *MemberHandler) CreateMember(c *gin.Context) {
+	var req CreateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	member := models.Member{
+		Name:    req.Name,
+		Email:   req.Email,
+		Phone:   req.Phone,
+		Subject: optionalString(req.Subject),
+	}
+
+	if err := h.memberService.CreateMember(c.Request.Context(), &member); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateMember godoc
+// @Summary Update a member
+// @Description update member by ID
+// @Tags members
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Member ID"
+// @Param member body UpdateMemberRequest true "Update member"
+// @Success 200 {object} models.Member
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/members/{id} [put]
+func (h *MemberHandler) UpdateMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid member ID")
+		return
+	}
+
+	var req UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	// Fetch the existing member from DB first
+	member, err := h.memberService.GetMember(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Update allowed fields
+	member.Name = req.Name
+	member.Email = req.Email
+	member.Phone = req.Phone
+	member.Subject = optionalString(req.Subject)
+
+	if err := h.memberService.UpdateMember(c.Request.Context(), member); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// DeleteMember godoc
+// @Summary Delete a member
+// @Description delete member by ID
+// @Tags members
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Member ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/members/{id} [delete]
+func (h *MemberHandler) DeleteMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid member ID")
+		return
+	}
+
+	if err := h.memberService.DeleteMember(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// optionalString maps an empty string to nil, for nullable columns
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
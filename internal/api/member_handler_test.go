@@ -0,0 +1,175 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMemberService struct {
+	mock.Mock
+}
+
+func (m *MockMemberService) CreateMember(ctx context.Context, member *models.Member) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockMemberService) UpdateMember(ctx context.Context, member *models.Member) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockMemberService) DeleteMember(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMemberService) GetMember(ctx context.Context, id uuid.UUID) (*models.Member, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Member), args.Error(1)
+}
+
+func (m *MockMemberService) GetMemberBySubject(ctx context.Context, subject string) (*models.Member, error) {
+	args := m.Called(ctx, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Member), args.Error(1)
+}
+
+func (m *MockMemberService) ListMembers(ctx context.Context, page, limit int) ([]models.Member, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]models.Member), args.Get(1).(int64), args.Error(2)
+}
+
+func TestMemberHandler_CreateMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMemberService)
+	handler := api.NewMemberHandler(mockService)
+
+	mockService.On("CreateMember", mock.Anything, mock.AnythingOfType("*models.Member")).Return(nil)
+
+	r := gin.Default()
+	r.POST("/members", handler.CreateMember)
+
+	body, _ := json.Marshal(api.CreateMemberRequest{Name: "Jane Reader", Email: "jane@example.com"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/members", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+
+	var response models.Member
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@example.com", response.Email)
+}
+
+func TestMemberHandler_CreateMember_InvalidEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMemberService)
+	handler := api.NewMemberHandler(mockService)
+
+	r := gin.Default()
+	r.POST("/members", handler.CreateMember)
+
+	body, _ := json.Marshal(api.CreateMemberRequest{Name: "Jane Reader", Email: "not-an-email"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/members", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	mockService.AssertNotCalled(t, "CreateMember", mock.Anything)
+}
+
+func TestMemberHandler_GetMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMemberService)
+	handler := api.NewMemberHandler(mockService)
+
+	memberID := uuid.New()
+	member := &models.Member{ID: memberID, Name: "Jane Reader", Email: "jane@example.com"}
+	mockService.On("GetMember", mock.Anything, memberID).Return(member, nil)
+
+	r := gin.Default()
+	r.GET("/members/:id", handler.GetMember)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/members/"+memberID.String(), nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Member
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, member.Name, response.Name)
+}
+
+func TestMemberHandler_UpdateMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMemberService)
+	handler := api.NewMemberHandler(mockService)
+
+	memberID := uuid.New()
+	existing := &models.Member{ID: memberID, Name: "Jane Reader", Email: "jane@example.com"}
+	mockService.On("GetMember", mock.Anything, memberID).Return(existing, nil)
+	mockService.On("UpdateMember", mock.Anything, mock.AnythingOfType("*models.Member")).Return(nil)
+
+	r := gin.Default()
+	r.PUT("/members/:id", handler.UpdateMember)
+
+	body, _ := json.Marshal(api.UpdateMemberRequest{Name: "Jane Reader", Email: "jane.reader@example.com", Phone: "555-0100"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/members/"+memberID.String(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Member
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "jane.reader@example.com", response.Email)
+	assert.Equal(t, "555-0100", response.Phone)
+}
+
+func TestMemberHandler_DeleteMember_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMemberService)
+	handler := api.NewMemberHandler(mockService)
+
+	memberID := uuid.New()
+	mockService.On("DeleteMember", mock.Anything, memberID).Return(apperr.NotFound("member not found"))
+
+	r := gin.Default()
+	r.DELETE("/members/:id", handler.DeleteMember)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/members/"+memberID.String(), nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics serves handler, the Prometheus registry's exposition endpoint
+// @Summary Prometheus metrics
+// @Description HTTP, connection pool and circulation metrics in the Prometheus text format
+// @Tags operations
+// @Produce  plain
+// @Success 200 {string} string "metrics in the Prometheus text exposition format"
+// @Router /metrics [get]
+func Metrics(handler http.Handler) gin.HandlerFunc {
+	return gin.WrapH(handler)
+}
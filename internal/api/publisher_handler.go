@@ -0,0 +1,289 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/service"
+)
+
+type PublisherHandler struct {
+	publisherService service.PublisherService
+	validate         *validator.Validate
+}
+
+func NewPublisherHandler(publisherService service.PublisherService) *PublisherHandler {
+	return &PublisherHandler{
+		publisherService: publisherService,
+		validate:         newValidator(),
+	}
+}
+
+// ListPublishers godoc
+// @Summary List all publishers
+// @Description get publishers
+// @Tags publishers
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success 200 {object} PageResponse{data=[]models.Publisher}
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/publishers [get]
+func (h *PublisherHandler) ListPublishers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	publishers, total, err := h.publisherService.ListPublishers(c.Request.Context(), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  publishers,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetPublisher godoc
+// @Summary Get a publisher
+// @Description get publisher by ID, including its parent and direct imprints
+// @Tags publishers
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Publisher ID"
+// @Success 200 {object} models.Publisher
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/publishers/{id} [get]
+func (h *PublisherHandler) GetPublisher(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid publisher ID")
+		return
+	}
+
+	publisher, err := h.publisherService.GetPublisher(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, publisher)
+}
+
+// CreatePublisher godoc
+// @Summary Create a publisher
+// @Description create new publisher, optionally as an imprint of parent_id
+// @Tags publishers
+// @Accept  json
+// @Produce  json
+// @Param publisher body CreatePublisherRequest true "Create publisher"
+// @Success 201 {object} models.Publisher
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/publishers [post]
+func (h *PublisherHandler) CreatePublisher(c *gin.Context) {
+	var req CreatePublisherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	publisher := models.Publisher{
+		Name:     req.Name,
+		Location: req.Location,
+		ParentID: req.ParentID,
+	}
+
+	if err := h.publisherService.CreatePublisher(c.Request.Context(), &publisher); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, publisher)
+}
+
+// UpdatePublisher godoc
+// @Summary Update a publisher
+// @Description update publisher by ID
+// @Tags publishers
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Publisher ID"
+// @Param publisher body UpdatePublisherRequest true "Update publisher"
+// @Success 200 {object} models.Publisher
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/publishers/{id} [put]
+func (h *PublisherHandler) UpdatePublisher(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid publisher ID")
+		return
+	}
+
+	var req UpdatePublisherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	// Fetch the existing publisher from DB first
+	publisher, err := h.publisherService.GetPublisher(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Update allowed fields
+	publisher.Name = req.Name
+	publisher.Location = req.Location
+	publisher.ParentID = req.ParentID
+	publisher.Parent = nil
+
+	if err := h.publisherService.UpdatePublisher(c.Request.Context(), publisher); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, publisher)
+}
+
+// DeletePublisher godoc
+// @Summary Delete a publisher
+// @Description delete publisher by ID; its imprints become top-level publishers
+// @Tags publishers
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Publisher ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/publishers/{id} [delete]
+func (h *PublisherHandler) DeletePublisher(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid publisher ID")
+		return
+	}
+
+	if err := h.publisherService.DeletePublisher(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListImprints godoc
+// @Summary List a publisher's imprints
+// @Description get the direct imprints of a publisher
+// @Tags publishers
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Publisher ID"
+// @Success 200 {object} ListResponse{data=[]models.Publisher}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/publishers/{id}/imprints [get]
+func (h *PublisherHandler) ListImprints(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid publisher ID")
+		return
+	}
+
+	imprints, err := h.publisherService.ListImprints(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": imprints})
+}
+
+// ListPublisherBooks godoc
+// @Summary List a publisher's books
+// @Description get the books of a publisher, optionally including every imprint below it
+// @Tags publishers
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Publisher ID"
+// @Param include_imprints query bool false "Include books of all imprints"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success 200 {object} PageResponse{data=[]models.Book}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/publishers/{id}/books [get]
+func (h *PublisherHandler) ListPublisherBooks(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Invalid publisher ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	includeImprints, _ := strconv.ParseBool(c.DefaultQuery("include_imprints", "false"))
+
+	books, total, err := h.publisherService.ListPublisherBooks(c.Request.Context(), id, includeImprints, page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  books,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
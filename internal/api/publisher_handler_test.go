@@ -0,0 +1,181 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPublisherService struct {
+	mock.Mock
+}
+
+func (m *MockPublisherService) CreatePublisher(ctx context.Context, publisher *models.Publisher) error {
+	args := m.Called(ctx, publisher)
+	return args.Error(0)
+}
+
+func (m *MockPublisherService) UpdatePublisher(ctx context.Context, publisher *models.Publisher) error {
+	args := m.Called(ctx, publisher)
+	return args.Error(0)
+}
+
+func (m *MockPublisherService) DeletePublisher(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPublisherService) GetPublisher(ctx context.Context, id uuid.UUID) (*models.Publisher, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherService) ListPublishers(ctx context.Context, page, limit int) ([]models.Publisher, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]models.Publisher), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPublisherService) ListImprints(ctx context.Context, id uuid.UUID) ([]models.Publisher, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]models.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherService) ListPublisherBooks(ctx context.Context, id uuid.UUID, includeImprints bool, page, limit int) ([]models.Book, int64, error) {
+	args := m.Called(ctx, id, includeImprints, page, limit)
+	return args.Get(0).([]models.Book), args.Get(1).(int64), args.Error(2)
+}
+
+func TestPublisherHandler_CreatePublisher(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockPublisherService)
+	handler := api.NewPublisherHandler(mockService)
+
+	parentID := uuid.New()
+	mockService.On("CreatePublisher", mock.Anything, mock.MatchedBy(func(p *models.Publisher) bool {
+		return p.Name == "Vintage" && p.ParentID != nil && *p.ParentID == parentID
+	})).Return(nil)
+
+	r := gin.Default()
+	r.POST("/publishers", handler.CreatePublisher)
+
+	body, _ := json.Marshal(api.CreatePublisherRequest{Name: "Vintage", Location: "London", ParentID: &parentID})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/publishers", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPublisherHandler_UpdatePublisher(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockPublisherService)
+	handler := api.NewPublisherHandler(mockService)
+
+	publisherID := uuid.New()
+	existing := &models.Publisher{ID: publisherID, Name: "Old Name"}
+
+	mockService.On("GetPublisher", mock.Anything, publisherID).Return(existing, nil)
+	mockService.On("UpdatePublisher", mock.Anything, mock.AnythingOfType("*models.Publisher")).Return(nil)
+
+	r := gin.Default()
+	r.PUT("/publishers/:id", handler.UpdatePublisher)
+
+	body, _ := json.Marshal(api.UpdatePublisherRequest{Name: "New Name", Location: "New York"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/publishers/"+publisherID.String(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Publisher
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "New Name", response.Name)
+	assert.Equal(t, "New York", response.Location)
+}
+
+func TestPublisherHandler_ListImprints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockPublisherService)
+	handler := api.NewPublisherHandler(mockService)
+
+	publisherID := uuid.New()
+	imprints := []models.Publisher{{Name: "Vintage", ParentID: &publisherID}}
+	mockService.On("ListImprints", mock.Anything, publisherID).Return(imprints, nil)
+
+	r := gin.Default()
+	r.GET("/publishers/:id/imprints", handler.ListImprints)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/publishers/"+publisherID.String()+"/imprints", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response struct {
+		Data []models.Publisher `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Data, 1)
+}
+
+func TestPublisherHandler_ListPublisherBooks_IncludeImprints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockPublisherService)
+	handler := api.NewPublisherHandler(mockService)
+
+	publisherID := uuid.New()
+	books := []models.Book{{Title: "Group Book"}}
+	mockService.On("ListPublisherBooks", mock.Anything, publisherID, true, 1, 10).Return(books, int64(1), nil)
+
+	r := gin.Default()
+	r.GET("/publishers/:id/books", handler.ListPublisherBooks)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/publishers/"+publisherID.String()+"/books?include_imprints=true", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPublisherHandler_DeletePublisher_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockPublisherService)
+	handler := api.NewPublisherHandler(mockService)
+
+	publisherID := uuid.New()
+	mockService.On("DeletePublisher", mock.Anything, publisherID).Return(apperr.NotFound("publisher not found"))
+
+	r := gin.Default()
+	r.DELETE("/publishers/:id", handler.DeletePublisher)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/publishers/"+publisherID.String(), nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/library-api/internal/service"
+)
+
+type SearchHandler struct {
+	searchService service.SearchService
+	validate      *validator.Validate
+}
+
+func NewSearchHandler(searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+		validate:      newValidator(),
+	}
+}
+
+// Search godoc
+// @Summary Full-text search
+// @Description search books by title, author, publisher and genre, best matches first
+// @Tags search
+// @Accept  json
+// @Produce  json
+// @Param q query string true "Search terms; supports \"quoted phrases\", OR and -exclusions"
+// @Param page query int false "Page number (max 10000)"
+// @Param limit query int false "Number of items per page (max 100)"
+// @Success 200 {object} PageResponse{data=[]repository.BookSearchResult}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	var query SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	query.Q = strings.TrimSpace(query.Q)
+	if err := h.validate.Struct(query); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	results, total, err := h.searchService.SearchBooks(c.Request.Context(), query.Q, query.Page, query.Limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  results,
+		"total": total,
+		"page":  query.Page,
+		"limit": query.Limit,
+	})
+}
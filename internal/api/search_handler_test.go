@@ -0,0 +1,79 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSearchService) SearchBooks(ctx context.Context, query string, page, limit int) ([]repository.BookSearchResult, int64, error) {
+	args := m.Called(ctx, query, page, limit)
+	return args.Get(0).([]repository.BookSearchResult), args.Get(1).(int64), args.Error(2)
+}
+
+func TestSearchHandler_Search(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockSearchService)
+	handler := api.NewSearchHandler(mockService)
+
+	results := []repository.BookSearchResult{
+		{
+			Book:      models.Book{Title: "Dune"},
+			Rank:      0.5,
+			Highlight: "<mark>Dune</mark> | Frank Herbert",
+		},
+	}
+	mockService.On("SearchBooks", mock.Anything, "dune", 2, 5).Return(results, int64(6), nil)
+
+	r := gin.Default()
+	r.GET("/search", handler.Search)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/search?q=+dune+&page=2&limit=5", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response struct {
+		Data  []repository.BookSearchResult `json:"data"`
+		Total int64                         `json:"total"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), response.Total)
+	assert.Equal(t, "<mark>Dune</mark> | Frank Herbert", response.Data[0].Highlight)
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchHandler_Search_MissingQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockSearchService)
+	handler := api.NewSearchHandler(mockService)
+
+	r := gin.Default()
+	r.GET("/search", handler.Search)
+
+	for _, query := range []string{"", "q=+++", "q=dune&limit=0", "q=dune&page=10001"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/search?"+query, nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 400, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "SearchBooks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/auth"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/service"
+)
+
+// currentMember returns the member linked to the caller's token subject.
+// When there is none it writes the response and returns false.
+func currentMember(c *gin.Context, memberService service.MemberService) (*models.Member, bool) {
+	principal, ok := auth.CurrentPrincipal(c)
+	if !ok {
+		respondProblem(c, http.StatusUnauthorized, "Authentication required")
+		return nil, false
+	}
+
+	member, err := memberService.GetMemberBySubject(c.Request.Context(), principal.Subject)
+	if errors.Is(err, apperr.ErrNotFound) {
+		respondProblem(c, http.StatusForbidden, "No member is linked to this account")
+		return nil, false
+	}
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	return member, true
+}
+
+// SelfHandler serves the /me routes, which show members their own records
+type SelfHandler struct {
+	memberService service.MemberService
+	loanService   service.LoanService
+	holdService   service.HoldService
+	fineService   service.FineService
+}
+
+func NewSelfHandler(memberService service.MemberService, loanService service.LoanService, holdService service.HoldService, fineService service.FineService) *SelfHandler {
+	return &SelfHandler{
+		memberService: memberService,
+		loanService:   loanService,
+		holdService:   holdService,
+		fineService:   fineService,
+	}
+}
+
+// GetMe godoc
+// @Summary Get the caller's member record
+// @Description get the member linked to the subject of the caller's token
+// @Tags me
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} models.Member
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/v1/me [get]
+func (h *SelfHandler) GetMe(c *gin.Context) {
+	member, ok := currentMember(c, h.memberService)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// ListMyLoans godoc
+// @Summary List the caller's loans
+// @Description get active and historical loans of the caller's member record, newest first
+// @Tags me
+// @Accept  json
+// @Produce  json
+// @Param status query string false "active, returned, overdue or all (default)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Success 200 {object} PageResponse{data=[]models.Loan}
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/v1/me/loans [get]
+func (h *SelfHandler) ListMyLoans(c *gin.Context) {
+	status, ok := loanStatus(c)
+	if !ok {
+		respondProblem(c, http.StatusBadRequest, "Invalid loan status")
+		return
+	}
+
+	member, ok := currentMember(c, h.memberService)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	loans, total, err := h.loanService.ListMemberLoans(c.Request.Context(), member.ID, status, page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  loans,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ListMyHolds godoc
+// @Summary List the caller's holds
+// @Description get every hold placed by the caller's member record, newest first
+// @Tags me
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} ListResponse{data=[]models.Hold}
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/v1/me/holds [get]
+func (h *SelfHandler) ListMyHolds(c *gin.Context) {
+	member, ok := currentMember(c, h.memberService)
+	if !ok {
+		return
+	}
+
+	holds, err := h.holdService.ListMemberHolds(c.Request.Context(), member.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": holds})
+}
+
+// ListMyFines godoc
+// @Summary List the caller's fines
+// @Description get every fine of the caller's member record and their total outstanding balance in cents
+// @Tags me
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} FineListResponse{data=[]models.Fine}
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/v1/me/fines [get]
+func (h *SelfHandler) ListMyFines(c *gin.Context) {
+	member, ok := currentMember(c, h.memberService)
+	if !ok {
+		return
+	}
+
+	fines, balance, err := h.fineService.ListMemberFines(c.Request.Context(), member.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":          fines,
+		"balance_cents": balance,
+	})
+}
@@ -0,0 +1,89 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/auth"
+	"github.com/library-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSelfRouter(subject string) (*gin.Engine, *MockMemberService, *MockLoanService, *MockHoldService, *MockFineService) {
+	gin.SetMode(gin.TestMode)
+
+	memberService := new(MockMemberService)
+	loanService := new(MockLoanService)
+	holdService := new(MockHoldService)
+	fineService := new(MockFineService)
+	handler := api.NewSelfHandler(memberService, loanService, holdService, fineService)
+
+	r := gin.Default()
+	r.Use(asPrincipal(subject, auth.RoleMember))
+	r.GET("/me", handler.GetMe)
+	r.GET("/me/loans", handler.ListMyLoans)
+	r.GET("/me/holds", handler.ListMyHolds)
+	r.GET("/me/fines", handler.ListMyFines)
+	return r, memberService, loanService, holdService, fineService
+}
+
+func TestSelfHandler_GetMe(t *testing.T) {
+	r, memberService, _, _, _ := newSelfRouter("alice")
+
+	subject := "alice"
+	member := &models.Member{ID: uuid.New(), Name: "alice", Subject: &subject}
+	memberService.On("GetMemberBySubject", mock.Anything, "alice").Return(member, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/me", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Member
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, member.ID, response.ID)
+}
+
+func TestSelfHandler_ListsOnlyOwnRecords(t *testing.T) {
+	r, memberService, loanService, holdService, fineService := newSelfRouter("alice")
+
+	member := &models.Member{ID: uuid.New(), Name: "alice"}
+	memberService.On("GetMemberBySubject", mock.Anything, "alice").Return(member, nil)
+	loanService.On("ListMemberLoans", mock.Anything, member.ID, "active", 1, 10).Return([]models.Loan{{MemberID: member.ID}}, int64(1), nil)
+	holdService.On("ListMemberHolds", mock.Anything, member.ID).Return([]models.Hold{{MemberID: member.ID}}, nil)
+	fineService.On("ListMemberFines", mock.Anything, member.ID).Return([]models.Fine{{MemberID: member.ID}}, int64(250), nil)
+
+	for _, path := range []string{"/me/loans?status=active", "/me/holds", "/me/fines"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code, path)
+	}
+	loanService.AssertExpectations(t)
+	holdService.AssertExpectations(t)
+	fineService.AssertExpectations(t)
+}
+
+func TestSelfHandler_NoLinkedMember(t *testing.T) {
+	r, memberService, _, _, _ := newSelfRouter("librarian-1")
+
+	memberService.On("GetMemberBySubject", mock.Anything, "librarian-1").Return(nil, apperr.NotFound("member not found"))
+
+	for _, path := range []string{"/me", "/me/loans", "/me/holds", "/me/fines"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 403, w.Code, path)
+	}
+}
@@ -0,0 +1,59 @@
+// Package apperr defines the kinds of failure shared by the repository,
+// service and API layers. Lower layers return them, wrapped as needed, and
+// the API maps each kind to a single HTTP status.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds, matched with errors.Is
+var (
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means the request clashes with the current state, e.g. a
+	// duplicate key or a book with no copies left to issue
+	ErrConflict = errors.New("conflict")
+	// ErrValidation means the input is invalid regardless of current state
+	ErrValidation = errors.New("validation failed")
+	// ErrUnavailable means a dependency such as the database is down or
+	// timed out; the request may succeed if retried
+	ErrUnavailable = errors.New("service unavailable")
+)
+
+// Error is a failure of a given kind with a message fit for clients
+type Error struct {
+	kind  error
+	msg   string
+	cause error
+}
+
+func (e *Error) Error() string { return e.msg }
+
+// Is matches the error's kind
+func (e *Error) Is(target error) bool { return target == e.kind }
+
+func (e *Error) Unwrap() error { return e.cause }
+
+func NotFound(format string, args ...interface{}) error {
+	return &Error{kind: ErrNotFound, msg: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(format string, args ...interface{}) error {
+	return &Error{kind: ErrConflict, msg: fmt.Sprintf(format, args...)}
+}
+
+func Validation(format string, args ...interface{}) error {
+	return &Error{kind: ErrValidation, msg: fmt.Sprintf(format, args...)}
+}
+
+// Wrap gives cause a kind and client message while keeping it in the chain
+func Wrap(kind, cause error, format string, args ...interface{}) error {
+	return &Error{kind: kind, msg: fmt.Sprintf(format, args...), cause: cause}
+}
+
+// IsTyped reports whether err carries one of the kinds above
+func IsTyped(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
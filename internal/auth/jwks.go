@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/MicahParks/jwkset"
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeySet resolves the key a token is verified with, as a jwt.Keyfunc
+type KeySet interface {
+	Keyfunc(token *jwt.Token) (interface{}, error)
+}
+
+type hmacKeySet []byte
+
+func (s hmacKeySet) Keyfunc(*jwt.Token) (interface{}, error) {
+	return []byte(s), nil
+}
+
+// HMACKeySet verifies HS256 tokens with a shared secret
+func HMACKeySet(secret []byte) KeySet {
+	return hmacKeySet(secret)
+}
+
+// FileKeySet reads a JWKS document from disk and re-reads it when the file
+// changes, so keys can be rotated by replacing the file.
+type FileKeySet struct {
+	path string
+	// checkEvery rate-limits the modification time check
+	checkEvery time.Duration
+
+	mu        sync.Mutex
+	keys      keyfunc.Keyfunc
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewFileKeySet loads the JWKS at path, failing if it is missing or invalid
+func NewFileKeySet(path string, checkEvery time.Duration) (*FileKeySet, error) {
+	s := &FileKeySet{path: path, checkEvery: checkEvery}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileKeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	s.mu.Lock()
+	if time.Since(s.checkedAt) >= s.checkEvery {
+		// Keep serving the previous keys if the new file is unreadable
+		_ = s.reload()
+	}
+	keys := s.keys
+	s.mu.Unlock()
+
+	return keys.Keyfunc(token)
+}
+
+// reload re-parses the file if its modification time changed. Callers must
+// hold s.mu, except during construction.
+func (s *FileKeySet) reload() error {
+	s.checkedAt = time.Now()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	if s.keys != nil && info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.modTime = info.ModTime()
+	return nil
+}
+
+func parseJWKS(data []byte) (keyfunc.Keyfunc, error) {
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("invalid JWKS: malformed JSON")
+	}
+	keys, err := keyfunc.NewJWKSetJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	all, err := keys.Storage().KeyReadAll(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+	signing := 0
+	for _, key := range all {
+		if use := key.Marshal().USE; use == "" || use == jwkset.UseSig {
+			signing++
+		}
+	}
+	if signing == 0 {
+		return nil, fmt.Errorf("JWKS contains no signing keys")
+	}
+
+	// Encryption keys cannot verify signatures
+	return keyfunc.New(keyfunc.Options{
+		Storage:      keys.Storage(),
+		UseWhitelist: []jwkset.USE{jwkset.UseSig, ""},
+	})
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// ErrInvalidToken is returned for any token that fails verification
+var ErrInvalidToken = errors.New("invalid token")
+
+// VerifierConfig controls which tokens a Verifier accepts
+type VerifierConfig struct {
+	Keys KeySet
+	// Issuer, when set, must equal the iss claim
+	Issuer string
+	// Audience, when set, must be one of the aud claim values
+	Audience string
+	// Leeway tolerates clock skew when checking exp, nbf and iat
+	Leeway time.Duration
+	// RolesClaim names the claim holding the principal's roles
+	RolesClaim string
+}
+
+// Verifier checks signed JWTs (RFC 7519) and turns them into principals.
+// Parsing and signature checks are left to golang-jwt; the verifier decides
+// which keys, algorithms, issuer and audience are acceptable.
+type Verifier struct {
+	config VerifierConfig
+	parser *jwt.Parser
+}
+
+func NewVerifier(config VerifierConfig) *Verifier {
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+
+	options := []jwt.ParserOption{
+		// The key's type decides the algorithm, so a token cannot downgrade
+		// an RSA key to HMAC or claim "none"
+		jwt.WithValidMethods([]string{AlgHS256, AlgRS256}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(config.Leeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &Verifier{config: config, parser: jwt.NewParser(options...)}
+}
+
+// Verify checks the token's signature and registered claims and returns the
+// principal it identifies. All failures wrap ErrInvalidToken.
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.config.Keys.Keyfunc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return &Principal{
+		Subject: subject,
+		Roles:   stringList(claims[v.config.RolesClaim]),
+		Claims:  claims,
+	}, nil
+}
+
+// stringList reads a claim that may be a single string or an array of them
+func stringList(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/library-api/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("test-secret-at-least-32-bytes-long!!")
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret []byte, kid string, claims map[string]interface{}) string {
+	header := map[string]string{"alg": "HS256", "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header := map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "user-1",
+		"iss":   "https://auth.example.com",
+		"aud":   []string{"library-api", "other"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"roles": []string{"librarian"},
+	}
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]string) {
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestVerifier_HS256(t *testing.T) {
+	verifier := auth.NewVerifier(auth.VerifierConfig{
+		Keys:     auth.HMACKeySet(testSecret),
+		Issuer:   "https://auth.example.com",
+		Audience: "library-api",
+	})
+
+	principal, err := verifier.Verify(signHS256(t, testSecret, "", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", principal.Subject)
+	assert.Equal(t, []string{"librarian"}, principal.Roles)
+	assert.True(t, principal.HasRole("librarian"))
+
+	tests := map[string]func(claims map[string]interface{}){
+		"expired":         func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"missing expiry":  func(c map[string]interface{}) { delete(c, "exp") },
+		"not yet valid":   func(c map[string]interface{}) { c["nbf"] = time.Now().Add(time.Hour).Unix() },
+		"wrong issuer":    func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" },
+		"wrong audience":  func(c map[string]interface{}) { c["aud"] = "someone-else" },
+		"missing subject": func(c map[string]interface{}) { delete(c, "sub") },
+	}
+	for name, mutate := range tests {
+		claims := validClaims()
+		mutate(claims)
+		_, err := verifier.Verify(signHS256(t, testSecret, "", claims))
+		assert.ErrorIs(t, err, auth.ErrInvalidToken, name)
+	}
+
+	// Wrong secret
+	_, err = verifier.Verify(signHS256(t, []byte("another-secret"), "", validClaims()))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// Tampered claims
+	token := signHS256(t, testSecret, "", validClaims())
+	claims := validClaims()
+	claims["roles"] = []string{"admin"}
+	forged := signHS256(t, testSecret, "", claims)
+	_, err = verifier.Verify(token[:len(token)-43] + forged[len(forged)-43:])
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// alg=none is never accepted
+	unsigned := encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, validClaims()) + "."
+	_, err = verifier.Verify(unsigned)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, err = verifier.Verify("not-a-token")
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestVerifier_JWKSRotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, rsaJWK("old", &oldKey.PublicKey))
+
+	keys, err := auth.NewFileKeySet(path, 0)
+	require.NoError(t, err)
+	verifier := auth.NewVerifier(auth.VerifierConfig{Keys: keys})
+
+	_, err = verifier.Verify(signRS256(t, oldKey, "old", validClaims()))
+	assert.NoError(t, err)
+	_, err = verifier.Verify(signRS256(t, newKey, "new", validClaims()))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// An RS256 key cannot be used as an HMAC secret
+	_, err = verifier.Verify(signHS256(t, oldKey.PublicKey.N.Bytes(), "old", validClaims()))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// Rotate: publish the new key alongside the old one, then retire the old
+	writeJWKS(t, path, rsaJWK("old", &oldKey.PublicKey), rsaJWK("new", &newKey.PublicKey))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+
+	_, err = verifier.Verify(signRS256(t, newKey, "new", validClaims()))
+	assert.NoError(t, err)
+
+	writeJWKS(t, path, rsaJWK("new", &newKey.PublicKey))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Second)))
+
+	_, err = verifier.Verify(signRS256(t, oldKey, "old", validClaims()))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// A broken file keeps the last good keys
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(3*time.Second)))
+
+	_, err = verifier.Verify(signRS256(t, newKey, "new", validClaims()))
+	assert.NoError(t, err)
+}
+
+func TestNewFileKeySet_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := auth.NewFileKeySet(filepath.Join(dir, "missing.json"), time.Minute)
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "empty.json")
+	writeJWKS(t, path)
+	_, err = auth.NewFileKeySet(path, time.Minute)
+	assert.Error(t, err)
+
+	// Encryption keys cannot verify signatures
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	encryption := rsaJWK("enc", &key.PublicKey)
+	encryption["use"] = "enc"
+	path = filepath.Join(dir, "enc.json")
+	writeJWKS(t, path, encryption)
+	_, err = auth.NewFileKeySet(path, time.Minute)
+	assert.Error(t, err)
+}
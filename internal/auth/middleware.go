@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/logging"
+	"github.com/library-api/internal/problem"
+)
+
+// APIKeyHeader carries an API key in place of a bearer token
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator resolves an API key to the principal it acts as. It
+// returns an apperr.ErrNotFound error for keys that do not authenticate.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// Middleware rejects requests without a valid bearer token or, when apiKeys
+// is not nil, API key, and stores the authenticated principal for the rest
+// of the chain.
+func Middleware(verifier *Verifier, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(APIKeyHeader); key != "" && apiKeys != nil {
+			principal, err := apiKeys.AuthenticateAPIKey(c.Request.Context(), key)
+			if errors.Is(err, apperr.ErrNotFound) {
+				problem.Abort(c, problem.New(http.StatusUnauthorized, "Invalid, expired or revoked API key"))
+				return
+			}
+			if err != nil {
+				// The key may well be valid; a 401 would tell the client to
+				// stop using it
+				ctx := c.Request.Context()
+				logging.FromContext(ctx).ErrorContext(ctx, "api key lookup failed", "error", err)
+				problem.Abort(c, problem.New(http.StatusServiceUnavailable, "API keys cannot be checked right now"))
+				return
+			}
+			setPrincipal(c, principal)
+			c.Next()
+			return
+		}
+
+		scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="library-api"`)
+			problem.Abort(c, problem.New(http.StatusUnauthorized, "Missing bearer token"))
+			return
+		}
+
+		principal, err := verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="library-api", error="invalid_token"`)
+			problem.Abort(c, problem.New(http.StatusUnauthorized, "Invalid or expired token"))
+			return
+		}
+
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
@@ -0,0 +1,109 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/auth"
+	"github.com/library-api/internal/problem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verifier := auth.NewVerifier(auth.VerifierConfig{Keys: auth.HMACKeySet(testSecret)})
+
+	r := gin.New()
+	r.Use(auth.Middleware(verifier, nil))
+	r.GET("/whoami", func(c *gin.Context) {
+		fromGin, _ := auth.CurrentPrincipal(c)
+		fromCtx, _ := auth.PrincipalFromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"gin": fromGin.Subject, "ctx": fromCtx.Subject})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+signHS256(t, testSecret, "", validClaims()))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.JSONEq(t, `{"gin":"user-1","ctx":"user-1"}`, w.Body.String())
+
+	for name, header := range map[string]string{
+		"missing":      "",
+		"wrong scheme": "Basic dXNlcjpwYXNz",
+		"bad token":    "Bearer not-a-token",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/whoami", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 401, w.Code, name)
+		assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer", name)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"), name)
+	}
+}
+
+type stubAPIKeys map[string]*auth.Principal
+
+func (s stubAPIKeys) AuthenticateAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	if key == "lib_outage_secret" {
+		return nil, errors.New("connection refused")
+	}
+	if p, ok := s[key]; ok {
+		return p, nil
+	}
+	return nil, apperr.NotFound("API key not found")
+}
+
+func TestMiddleware_APIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verifier := auth.NewVerifier(auth.VerifierConfig{Keys: auth.HMACKeySet(testSecret)})
+	keys := stubAPIKeys{"lib_kiosk_secret": {Subject: "api-key:kiosk", Scopes: []auth.Permission{auth.PermCatalogRead}}}
+
+	r := gin.New()
+	r.Use(auth.Middleware(verifier, keys))
+	r.GET("/books", auth.Require(auth.PermCatalogRead), func(c *gin.Context) {
+		p, _ := auth.CurrentPrincipal(c)
+		c.String(http.StatusOK, p.Subject)
+	})
+	r.DELETE("/books", auth.Require(auth.PermCatalogDelete), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books", nil)
+	req.Header.Set(auth.APIKeyHeader, "lib_kiosk_secret")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "api-key:kiosk", w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/books", nil)
+	req.Header.Set(auth.APIKeyHeader, "lib_kiosk_secret")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/books", nil)
+	req.Header.Set(auth.APIKeyHeader, "lib_unknown_secret")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 401, w.Code)
+
+	// A key that could not be checked is not reported as invalid
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/books", nil)
+	req.Header.Set(auth.APIKeyHeader, "lib_outage_secret")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 503, w.Code)
+}
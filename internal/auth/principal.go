@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// Subject is the token's sub claim
+	Subject string
+	Roles   []string
+	// Scopes grants individual permissions, as API keys carry no roles
+	Scopes []Permission
+	// Claims holds every claim of the verified token
+	Claims map[string]interface{}
+}
+
+// HasRole reports whether the principal was granted role
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// principalGinKey stores the principal on the gin.Context
+const principalGinKey = "auth.principal"
+
+// WithPrincipal returns a copy of ctx carrying p
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored by WithPrincipal
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// CurrentPrincipal returns the principal the middleware authenticated for c
+func CurrentPrincipal(c *gin.Context) (*Principal, bool) {
+	if v, ok := c.Get(principalGinKey); ok {
+		p, ok := v.(*Principal)
+		return p, ok
+	}
+	return PrincipalFromContext(c.Request.Context())
+}
+
+// setPrincipal makes p available both on c and on its request context, so
+// anything handed c.Request.Context() can see who is calling.
+func setPrincipal(c *gin.Context, p *Principal) {
+	c.Set(principalGinKey, p)
+	c.Request = c.Request.WithContext(WithPrincipal(c.Request.Context(), p))
+}
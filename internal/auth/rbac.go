@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/problem"
+)
+
+// Roles a principal can hold
+const (
+	RoleAdmin     = "admin"
+	RoleLibrarian = "librarian"
+	RoleMember    = "member"
+	RoleReadOnly  = "read-only"
+)
+
+// Permission names an operation a route requires
+type Permission string
+
+const (
+	PermCatalogRead  Permission = "catalog:read"
+	PermCatalogWrite Permission = "catalog:write"
+	// PermCatalogDelete covers deleting books, authors and publishers
+	PermCatalogDelete Permission = "catalog:delete"
+	// PermCirculationRead covers loans, hold queues and fines
+	PermCirculationRead Permission = "circulation:read"
+	// PermCirculationWrite covers issuing, returning and renewing
+	PermCirculationWrite Permission = "circulation:write"
+	// PermHoldsWrite covers placing and cancelling the caller's own holds
+	PermHoldsWrite Permission = "holds:write"
+	// PermHoldsManage extends holds:write to every member's holds
+	PermHoldsManage   Permission = "holds:manage"
+	PermMembersRead   Permission = "members:read"
+	PermMembersWrite  Permission = "members:write"
+	PermMembersDelete Permission = "members:delete"
+	PermFinesWrite    Permission = "fines:write"
+	PermAPIKeysManage Permission = "api-keys:manage"
+	// PermSelfRead covers the caller's own member record, loans, holds and
+	// fines
+	PermSelfRead Permission = "self:read"
+)
+
+// Permissions lists every permission, e.g. to validate API key scopes
+func Permissions() []Permission {
+	return []Permission{
+		PermCatalogRead, PermCatalogWrite, PermCatalogDelete,
+		PermCirculationRead, PermCirculationWrite, PermHoldsWrite, PermHoldsManage,
+		PermMembersRead, PermMembersWrite, PermMembersDelete,
+		PermFinesWrite, PermAPIKeysManage, PermSelfRead,
+	}
+}
+
+// rolePermissions is the permission matrix. Admins are granted everything
+// and are not listed.
+var rolePermissions = map[string][]Permission{
+	RoleReadOnly: {
+		PermCatalogRead,
+	},
+	RoleMember: {
+		PermCatalogRead,
+		PermHoldsWrite,
+		PermSelfRead,
+	},
+	RoleLibrarian: {
+		PermCatalogRead,
+		PermCatalogWrite,
+		PermCirculationRead,
+		PermCirculationWrite,
+		PermHoldsWrite,
+		PermHoldsManage,
+		PermMembersRead,
+		PermMembersWrite,
+		PermFinesWrite,
+		PermSelfRead,
+	},
+}
+
+// Can reports whether the principal's scopes or any of its roles grant perm
+func (p *Principal) Can(perm Permission) bool {
+	for _, scope := range p.Scopes {
+		if scope == perm {
+			return true
+		}
+	}
+	for _, role := range p.Roles {
+		if role == RoleAdmin {
+			return true
+		}
+		for _, granted := range rolePermissions[role] {
+			if granted == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Require only lets through principals granted perm. It must run after the
+// authentication middleware.
+func Require(perm Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			problem.Abort(c, problem.New(http.StatusUnauthorized, "Authentication required"))
+			return
+		}
+		if !principal.Can(perm) {
+			problem.Abort(c, problem.New(http.StatusForbidden, "Insufficient permissions"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// Anonymous authenticates every request as an admin. It stands in for
+// Middleware when authentication is disabled.
+func Anonymous() gin.HandlerFunc {
+	principal := &Principal{Subject: "anonymous", Roles: []string{RoleAdmin}}
+	return func(c *gin.Context) {
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
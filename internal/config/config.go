@@ -0,0 +1,393 @@
+// Package config loads the server settings. Each setting starts from a
+// built-in default and may be overridden, in increasing order of precedence,
+// by an optional YAML file, an optional .env file and environment variables.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/library-api/internal/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting the server reads at startup. The env tag names
+// the environment variable for a field and the yaml tag its key in the
+// config file.
+type Config struct {
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Circulation CirculationConfig `yaml:"circulation"`
+	Log         LogConfig         `yaml:"log"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+}
+
+// ServerConfig configures the HTTP listener. A zero timeout means none.
+type ServerConfig struct {
+	Port              int           `yaml:"port" env:"PORT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	// ShutdownDelay is how long the server keeps serving, with /readyz
+	// failing, after a shutdown signal, so load balancers stop routing to it
+	// before it stops accepting connections
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY"`
+	// ShutdownTimeout bounds how long in-flight requests may take to finish
+	// once the server is asked to stop
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+// DatabaseConfig configures the connection pool
+type DatabaseConfig struct {
+	URL             string        `yaml:"url" env:"DATABASE_URL"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+	// SlowQueryThreshold is how long a query may take before it is logged
+	// as slow; zero disables the warning
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold" env:"DB_SLOW_QUERY_THRESHOLD"`
+	// QueryTimeout bounds the database work of each book repository call;
+	// zero leaves only the request's own deadline
+	QueryTimeout time.Duration `yaml:"query_timeout" env:"DB_QUERY_TIMEOUT"`
+	// MigrateOnStart applies pending migrations before serving
+	MigrateOnStart bool `yaml:"migrate_on_start" env:"MIGRATE_ON_START"`
+}
+
+// AuthConfig configures bearer token verification. A JWKS file takes
+// precedence over a shared HS256 secret.
+type AuthConfig struct {
+	Disabled  bool   `yaml:"disabled" env:"AUTH_DISABLED"`
+	JWTSecret string `yaml:"jwt_secret" env:"JWT_SECRET"`
+	JWKSFile  string `yaml:"jwks_file" env:"JWT_JWKS_FILE"`
+	Issuer    string `yaml:"issuer" env:"JWT_ISSUER"`
+	Audience  string `yaml:"audience" env:"JWT_AUDIENCE"`
+}
+
+// CirculationConfig holds the loan, hold and fine rules
+type CirculationConfig struct {
+	LoanPeriodDays          int   `yaml:"loan_period_days" env:"LOAN_PERIOD_DAYS"`
+	MaxRenewals             int   `yaml:"max_renewals" env:"MAX_RENEWALS"`
+	HoldPickupDays          int   `yaml:"hold_pickup_days" env:"HOLD_PICKUP_DAYS"`
+	FineDailyRateCents      int64 `yaml:"fine_daily_rate_cents" env:"FINE_DAILY_RATE_CENTS"`
+	FineCapCents            int64 `yaml:"fine_cap_cents" env:"FINE_CAP_CENTS"`
+	FineBlockThresholdCents int64 `yaml:"fine_block_threshold_cents" env:"FINE_BLOCK_THRESHOLD_CENTS"`
+}
+
+// LogConfig configures the structured logger
+type LogConfig struct {
+	// Level is debug, info, warn or error; debug includes every SQL query
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+	// Format is json or text
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// TracingConfig configures span export. Tracing is off unless enabled, so
+// running locally needs no collector.
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" env:"TRACING_ENABLED"`
+	// Endpoint is the OTLP/HTTP collector base URL
+	Endpoint    string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName string `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	// SampleRatio is the share of new traces recorded, from 0 to 1
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// LookupFunc reads an environment variable, like os.LookupEnv
+type LookupFunc func(key string) (string, bool)
+
+// Default returns the settings used when nothing overrides them
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:              8080,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownDelay:     5 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:       20,
+			MaxIdleConns:       10,
+			ConnMaxLifetime:    30 * time.Minute,
+			ConnMaxIdleTime:    5 * time.Minute,
+			SlowQueryThreshold: 200 * time.Millisecond,
+			QueryTimeout:       10 * time.Second,
+			MigrateOnStart:     true,
+		},
+		Circulation: CirculationConfig{
+			LoanPeriodDays:          14,
+			MaxRenewals:             2,
+			HoldPickupDays:          3,
+			FineDailyRateCents:      25,
+			FineCapCents:            1000,
+			FineBlockThresholdCents: 500,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: logging.FormatJSON,
+		},
+		Tracing: TracingConfig{
+			Endpoint:    "http://localhost:4318",
+			ServiceName: "library-api",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Load reads a .env file from the working directory if there is one, then
+// builds the settings from the process environment
+func Load() (*Config, error) {
+	if err := loadDotEnv(); err != nil {
+		return nil, err
+	}
+	return LoadFrom(os.LookupEnv)
+}
+
+// LoadForMigrate is Load for the migrate subcommand, which only needs the
+// database, logging and tracing settings
+func LoadForMigrate() (*Config, error) {
+	if err := loadDotEnv(); err != nil {
+		return nil, err
+	}
+	return LoadForMigrateFrom(os.LookupEnv)
+}
+
+// LoadFrom builds the settings from the defaults, the YAML file named by
+// CONFIG_FILE if set, and the variables lookup returns, then validates them.
+// Any variable may instead be given as NAME_FILE, the path of a file holding
+// its value, which suits mounted secrets. Empty variables count as unset.
+func LoadFrom(lookup LookupFunc) (*Config, error) {
+	return load(lookup, (*Config).Validate)
+}
+
+// LoadForMigrateFrom is LoadFrom but only validates what ValidateForMigrate
+// does, so schema changes can run without the server's auth settings
+func LoadForMigrateFrom(lookup LookupFunc) (*Config, error) {
+	return load(lookup, (*Config).ValidateForMigrate)
+}
+
+func loadDotEnv() error {
+	// Variables already in the environment win over the .env file
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read .env: %w", err)
+	}
+	return nil
+}
+
+func load(lookup LookupFunc, validate func(*Config) error) (*Config, error) {
+	cfg := Default()
+
+	if path, ok := lookup("CONFIG_FILE"); ok && path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(cfg).Elem(), lookup); err != nil {
+		return nil, err
+	}
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var p problems
+	c.checkServer(&p)
+	c.checkDatabase(&p)
+	c.checkAuth(&p)
+	c.checkCirculation(&p)
+	c.checkObservability(&p)
+	return p.err()
+}
+
+// ValidateForMigrate reports every invalid database, logging or tracing
+// setting at once
+func (c *Config) ValidateForMigrate() error {
+	var p problems
+	c.checkDatabase(&p)
+	c.checkObservability(&p)
+	return p.err()
+}
+
+// problems collects invalid settings so they can be reported together
+type problems []error
+
+func (p *problems) check(ok bool, msg string) {
+	if !ok {
+		*p = append(*p, errors.New(msg))
+	}
+}
+
+func (p problems) err() error {
+	if len(p) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(p...))
+	}
+	return nil
+}
+
+func (c *Config) checkServer(p *problems) {
+	srv := c.Server
+	p.check(srv.Port > 0 && srv.Port <= 65535, "PORT must be between 1 and 65535")
+	p.check(srv.ReadTimeout >= 0, "HTTP_READ_TIMEOUT must not be negative")
+	p.check(srv.ReadHeaderTimeout >= 0, "HTTP_READ_HEADER_TIMEOUT must not be negative")
+	p.check(srv.WriteTimeout >= 0, "HTTP_WRITE_TIMEOUT must not be negative")
+	p.check(srv.IdleTimeout >= 0, "HTTP_IDLE_TIMEOUT must not be negative")
+	p.check(srv.ShutdownDelay >= 0, "SHUTDOWN_DELAY must not be negative")
+	p.check(srv.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+}
+
+func (c *Config) checkDatabase(p *problems) {
+	db := c.Database
+	p.check(db.URL != "", "DATABASE_URL is required")
+	p.check(db.MaxOpenConns >= 0, "DB_MAX_OPEN_CONNS must not be negative")
+	p.check(db.MaxIdleConns >= 0, "DB_MAX_IDLE_CONNS must not be negative")
+	p.check(db.MaxOpenConns == 0 || db.MaxIdleConns <= db.MaxOpenConns, "DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS")
+	p.check(db.ConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME must not be negative")
+	p.check(db.ConnMaxIdleTime >= 0, "DB_CONN_MAX_IDLE_TIME must not be negative")
+	p.check(db.SlowQueryThreshold >= 0, "DB_SLOW_QUERY_THRESHOLD must not be negative")
+	p.check(db.QueryTimeout >= 0, "DB_QUERY_TIMEOUT must not be negative")
+}
+
+func (c *Config) checkAuth(p *problems) {
+	a := c.Auth
+	if a.Disabled {
+		return
+	}
+	switch {
+	case a.JWKSFile != "":
+	case a.JWTSecret == "":
+		*p = append(*p, errors.New("set JWT_SECRET or JWT_JWKS_FILE, or AUTH_DISABLED=true to run without authentication"))
+	case len(a.JWTSecret) < 32:
+		*p = append(*p, errors.New("JWT_SECRET must be at least 32 bytes"))
+	}
+}
+
+func (c *Config) checkCirculation(p *problems) {
+	circ := c.Circulation
+	p.check(circ.LoanPeriodDays > 0, "LOAN_PERIOD_DAYS must be positive")
+	p.check(circ.MaxRenewals >= 0, "MAX_RENEWALS must not be negative")
+	p.check(circ.HoldPickupDays > 0, "HOLD_PICKUP_DAYS must be positive")
+	p.check(circ.FineDailyRateCents >= 0, "FINE_DAILY_RATE_CENTS must not be negative")
+	p.check(circ.FineCapCents >= 0, "FINE_CAP_CENTS must not be negative")
+	p.check(circ.FineBlockThresholdCents >= 0, "FINE_BLOCK_THRESHOLD_CENTS must not be negative")
+}
+
+func (c *Config) checkObservability(p *problems) {
+	p.check(logging.ValidLevel(c.Log.Level), "LOG_LEVEL must be debug, info, warn or error")
+	p.check(c.Log.Format == logging.FormatJSON || c.Log.Format == logging.FormatText, "LOG_FORMAT must be json or text")
+
+	if t := c.Tracing; t.Enabled {
+		p.check(t.Endpoint != "", "OTEL_EXPORTER_OTLP_ENDPOINT is required when tracing is enabled")
+		p.check(t.ServiceName != "", "OTEL_SERVICE_NAME is required when tracing is enabled")
+	}
+	p.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+}
+
+// applyEnv overrides the fields of v that have an env tag with the matching
+// variables, recursing into nested structs
+func applyEnv(v reflect.Value, lookup LookupFunc) error {
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(value, lookup); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		name := field.Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		raw, ok, err := lookupValue(name, lookup)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := setValue(value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lookupValue reads name directly or from the file named by name_FILE
+func lookupValue(name string, lookup LookupFunc) (string, bool, error) {
+	value, ok := lookup(name)
+	ok = ok && value != ""
+
+	path, fromFile := lookup(name + "_FILE")
+	if !fromFile || path == "" {
+		return value, ok, nil
+	}
+	if ok {
+		return "", false, fmt.Errorf("set only one of %s and %s_FILE", name, name)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s_FILE: %w", name, err)
+	}
+	// Secret files usually end with a newline that is not part of the value
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
+
+// setValue parses raw into v according to v's type
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 30s or 5m", raw)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/library-api/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) config.LookupFunc {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFrom_Defaults(t *testing.T) {
+	cfg, err := config.LoadFrom(env(map[string]string{
+		"DATABASE_URL":  "postgres://localhost/library",
+		"AUTH_DISABLED": "true",
+	}))
+	require.NoError(t, err)
+
+	want := config.Default()
+	want.Database.URL = "postgres://localhost/library"
+	want.Auth.Disabled = true
+	assert.Equal(t, want, cfg)
+}
+
+func TestLoadFrom_Layers(t *testing.T) {
+	yamlPath := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+database:
+  url: postgres://yaml/library
+  max_open_conns: 50
+  conn_max_lifetime: 1h
+auth:
+  disabled: true
+circulation:
+  loan_period_days: 21
+`)
+	secretPath := writeFile(t, "jwt_secret", "0123456789abcdef0123456789abcdef\n")
+
+	cfg, err := config.LoadFrom(env(map[string]string{
+		"CONFIG_FILE":       yamlPath,
+		"PORT":              "9100",
+		"DB_MAX_IDLE_CONNS": "5",
+		"AUTH_DISABLED":     "false",
+		"JWT_SECRET_FILE":   secretPath,
+		"JWT_ISSUER":        "",
+	}))
+	require.NoError(t, err)
+
+	// Environment beats YAML, YAML beats defaults, empty variables are ignored
+	assert.Equal(t, 9100, cfg.Server.Port)
+	assert.Equal(t, "postgres://yaml/library", cfg.Database.URL)
+	assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 5, cfg.Database.MaxIdleConns)
+	assert.Equal(t, time.Hour, cfg.Database.ConnMaxLifetime)
+	assert.Equal(t, 5*time.Minute, cfg.Database.ConnMaxIdleTime)
+	assert.Equal(t, 21, cfg.Circulation.LoanPeriodDays)
+	assert.Equal(t, 2, cfg.Circulation.MaxRenewals)
+	assert.False(t, cfg.Auth.Disabled)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", cfg.Auth.JWTSecret)
+	assert.Empty(t, cfg.Auth.Issuer)
+}
+
+func TestLoadFrom_Errors(t *testing.T) {
+	secretPath := writeFile(t, "db_url", "postgres://secret/library")
+
+	tests := []struct {
+		name string
+		vars map[string]string
+		want []string
+	}{
+		{
+			name: "unparseable values",
+			vars: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/library",
+				"AUTH_DISABLED":        "yes please",
+				"PORT":                 "eighty",
+				"DB_CONN_MAX_LIFETIME": "30",
+			},
+			want: []string{"AUTH_DISABLED", "PORT", "DB_CONN_MAX_LIFETIME"},
+		},
+		{
+			name: "value and file both set",
+			vars: map[string]string{
+				"DATABASE_URL":      "postgres://localhost/library",
+				"DATABASE_URL_FILE": secretPath,
+				"AUTH_DISABLED":     "true",
+			},
+			want: []string{"set only one of DATABASE_URL and DATABASE_URL_FILE"},
+		},
+		{
+			name: "missing secret file",
+			vars: map[string]string{
+				"DATABASE_URL_FILE": filepath.Join(t.TempDir(), "missing"),
+				"AUTH_DISABLED":     "true",
+			},
+			want: []string{"DATABASE_URL_FILE"},
+		},
+		{
+			name: "invalid settings are reported together",
+			vars: map[string]string{
+				"PORT":              "70000",
+				"DB_MAX_OPEN_CONNS": "5",
+				"LOAN_PERIOD_DAYS":  "0",
+				"JWT_SECRET":        "short",
+			},
+			want: []string{
+				"PORT must be between 1 and 65535",
+				"DATABASE_URL is required",
+				"DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS",
+				"LOAN_PERIOD_DAYS must be positive",
+				"JWT_SECRET must be at least 32 bytes",
+			},
+		},
+		{
+			name: "tracing settings",
+			vars: map[string]string{
+				"DATABASE_URL":                "postgres://localhost/library",
+				"AUTH_DISABLED":               "true",
+				"TRACING_ENABLED":             "true",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "",
+				"OTEL_SERVICE_NAME":           "",
+				"TRACING_SAMPLE_RATIO":        "1.5",
+			},
+			want: []string{"TRACING_SAMPLE_RATIO must be between 0 and 1"},
+		},
+		{
+			name: "authentication not configured",
+			vars: map[string]string{"DATABASE_URL": "postgres://localhost/library"},
+			want: []string{"set JWT_SECRET or JWT_JWKS_FILE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.LoadFrom(env(tt.vars))
+			require.Error(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestLoadFrom_BadConfigFile(t *testing.T) {
+	path := writeFile(t, "config.yaml", "server: [not, a, map]")
+
+	_, err := config.LoadFrom(env(map[string]string{"CONFIG_FILE": path}))
+	assert.ErrorContains(t, err, "failed to parse config file")
+}
+
+func TestLoadForMigrateFrom(t *testing.T) {
+	// The server's auth and circulation settings do not matter to migrate
+	cfg, err := config.LoadForMigrateFrom(env(map[string]string{
+		"DATABASE_URL":     "postgres://localhost/library",
+		"LOAN_PERIOD_DAYS": "0",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://localhost/library", cfg.Database.URL)
+
+	_, err = config.LoadForMigrateFrom(env(map[string]string{
+		"DB_MAX_OPEN_CONNS": "-1",
+		"LOG_FORMAT":        "xml",
+	}))
+	require.Error(t, err)
+	for _, want := range []string{
+		"DATABASE_URL is required",
+		"DB_MAX_OPEN_CONNS must not be negative",
+		"LOG_FORMAT must be json or text",
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+	assert.NotContains(t, err.Error(), "JWT_SECRET")
+}
@@ -0,0 +1,91 @@
+// Package isbn validates ISBN-10 and ISBN-13 numbers and converts them to
+// the canonical 13-digit form books are stored under.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrLength   = errors.New("ISBN must have 10 or 13 digits")
+	ErrChar     = errors.New("ISBN may only contain digits, hyphens and spaces, and X as the last ISBN-10 character")
+	ErrChecksum = errors.New("ISBN check digit is wrong")
+)
+
+// Normalize validates an ISBN-10 or ISBN-13, which may contain hyphens and
+// spaces, and returns it as 13 bare digits
+func Normalize(s string) (string, error) {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(s))
+	digits = strings.ToUpper(digits)
+
+	switch len(digits) {
+	case 10:
+		if err := checkISBN10(digits); err != nil {
+			return "", err
+		}
+		// ISBN-10s map into the 978 prefix, with the check digit recomputed
+		body := "978" + digits[:9]
+		return body + string(isbn13CheckDigit(body)), nil
+	case 13:
+		if err := checkISBN13(digits); err != nil {
+			return "", err
+		}
+		return digits, nil
+	default:
+		return "", ErrLength
+	}
+}
+
+// Valid reports whether s is a well-formed ISBN-10 or ISBN-13
+func Valid(s string) bool {
+	_, err := Normalize(s)
+	return err == nil
+}
+
+func checkISBN10(s string) error {
+	sum := 0
+	for i, r := range s {
+		var d int
+		switch {
+		case r >= '0' && r <= '9':
+			d = int(r - '0')
+		case r == 'X' && i == 9:
+			d = 10
+		default:
+			return ErrChar
+		}
+		sum += (10 - i) * d
+	}
+	if sum%11 != 0 {
+		return ErrChecksum
+	}
+	return nil
+}
+
+func checkISBN13(s string) error {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return ErrChar
+		}
+	}
+	if isbn13CheckDigit(s[:12]) != s[12] {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// isbn13CheckDigit returns the check digit for the first 12 digits of an
+// ISBN-13: weights alternate 1 and 3, and the digits must sum to a multiple
+// of 10
+func isbn13CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
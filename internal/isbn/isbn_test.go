@@ -0,0 +1,40 @@
+package isbn_test
+
+import (
+	"testing"
+
+	"github.com/library-api/internal/isbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	valid := map[string]string{
+		"9780306406157":     "9780306406157",
+		"978-0-306-40615-7": "9780306406157",
+		"978 0 306 40615 7": "9780306406157",
+		"0306406152":        "9780306406157",
+		"0-306-40615-2":     "9780306406157",
+		"080442957X":        "9780804429573",
+		"0-8044-2957-x":     "9780804429573",
+	}
+	for input, want := range valid {
+		got, err := isbn.Normalize(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	invalid := map[string]error{
+		"1234567890123": isbn.ErrChecksum,
+		"9780306406158": isbn.ErrChecksum,
+		"0306406153":    isbn.ErrChecksum,
+		"12345":         isbn.ErrLength,
+		"":              isbn.ErrLength,
+		"97803064061X7": isbn.ErrChar,
+		"X306406152":    isbn.ErrChar,
+	}
+	for input, want := range invalid {
+		_, err := isbn.Normalize(input)
+		assert.ErrorIs(t, err, want, input)
+		assert.False(t, isbn.Valid(input), input)
+	}
+}
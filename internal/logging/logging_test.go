@@ -0,0 +1,185 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/logging"
+	"github.com/library-api/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gorm.io/gorm"
+)
+
+// records decodes each JSON log line written to buf
+func records(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		out = append(out, record)
+	}
+	return out
+}
+
+func newRouter(buf *bytes.Buffer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logging.New(buf, "info", logging.FormatJSON)
+
+	r := gin.New()
+	r.Use(logging.RequestID(), logging.Middleware(logger), logging.Recovery(logger, func(c *gin.Context) {
+		problem.Abort(c, problem.New(http.StatusInternalServerError, "An unexpected error occurred"))
+	}))
+	r.GET("/books/:id", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).InfoContext(ctx, "handler ran")
+		problem.Write(c, problem.New(http.StatusNotFound, "book not found"))
+	})
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+	return r
+}
+
+func TestRequestID(t *testing.T) {
+	r := newRouter(new(bytes.Buffer))
+
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{"generated when missing", "", false},
+		{"propagated", "abc-123", true},
+		{"replaced when it has spaces", "abc 123", false},
+		{"replaced when too long", strings.Repeat("a", 129), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/books/1", nil)
+			if tt.header != "" {
+				req.Header.Set(logging.RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(logging.RequestIDHeader)
+			assert.NotEmpty(t, id)
+			if tt.wantSame {
+				assert.Equal(t, tt.header, id)
+			} else {
+				assert.NotEqual(t, tt.header, id)
+			}
+
+			// Error responses quote the same ID
+			var details problem.Details
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+			assert.Equal(t, id, details.RequestID)
+		})
+	}
+}
+
+func TestMiddleware_AccessLog(t *testing.T) {
+	buf := new(bytes.Buffer)
+	r := newRouter(buf)
+
+	req := httptest.NewRequest("GET", "/books/42", nil)
+	req.Header.Set(logging.RequestIDHeader, "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	logs := records(t, buf)
+	require.Len(t, logs, 2)
+
+	assert.Equal(t, "handler ran", logs[0]["msg"])
+	assert.Equal(t, "req-1", logs[0]["request_id"])
+
+	access := logs[1]
+	assert.Equal(t, "request", access["msg"])
+	assert.Equal(t, "WARN", access["level"])
+	assert.Equal(t, "req-1", access["request_id"])
+	assert.Equal(t, "GET", access["method"])
+	assert.Equal(t, "/books/42", access["path"])
+	assert.Equal(t, "/books/:id", access["route"])
+	assert.Equal(t, float64(http.StatusNotFound), access["status"])
+	assert.Contains(t, access, "duration_ms")
+}
+
+func TestRecovery(t *testing.T) {
+	buf := new(bytes.Buffer)
+	r := newRouter(buf)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+
+	logs := records(t, buf)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "panic while handling request", logs[0]["msg"])
+	assert.Equal(t, "boom", logs[0]["panic"])
+	assert.NotEmpty(t, logs[0]["request_id"])
+	assert.Equal(t, "ERROR", logs[1]["level"])
+}
+
+func TestNew_TraceContext(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := logging.New(buf, "info", logging.FormatJSON)
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "work")
+	logger.InfoContext(ctx, "inside span")
+	logger.Info("outside span")
+
+	logs := records(t, buf)
+	require.Len(t, logs, 2)
+	assert.Equal(t, span.SpanContext().TraceID().String(), logs[0]["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), logs[0]["span_id"])
+	assert.NotContains(t, logs[1], "trace_id")
+}
+
+func TestGormLogger(t *testing.T) {
+	query := func() (string, int64) { return "SELECT * FROM books WHERE id = $1", 1 }
+	ctx := logging.WithRequestID(context.Background(), "req-2")
+
+	tests := []struct {
+		name      string
+		level     string
+		elapsed   time.Duration
+		err       error
+		wantLevel string
+		wantMsg   string
+	}{
+		{"fast query hidden at info", "info", time.Millisecond, nil, "", ""},
+		{"fast query shown at debug", "debug", time.Millisecond, nil, "DEBUG", "query"},
+		{"slow query", "info", time.Second, nil, "WARN", "slow query"},
+		{"failed query", "info", time.Millisecond, errors.New("syntax error"), "ERROR", "query failed"},
+		{"missing row is not a failure", "info", time.Millisecond, gorm.ErrRecordNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			l := logging.NewGormLogger(logging.New(buf, tt.level, logging.FormatJSON), 100*time.Millisecond)
+			l.Trace(ctx, time.Now().Add(-tt.elapsed), query, tt.err)
+
+			logs := records(t, buf)
+			if tt.wantMsg == "" {
+				assert.Empty(t, logs)
+				return
+			}
+			require.Len(t, logs, 1)
+			assert.Equal(t, tt.wantLevel, logs[0]["level"])
+			assert.Equal(t, tt.wantMsg, logs[0]["msg"])
+			assert.Equal(t, "SELECT * FROM books WHERE id = $1", logs[0]["sql"])
+			assert.Equal(t, "req-2", logs[0]["request_id"])
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"log/slog"
+	"math"
+
+	"github.com/library-api/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Circulation counts issues, returns and refused issues, and reports how
+// many copies are on loan
+type Circulation struct {
+	issued   prometheus.Counter
+	returned prometheus.Counter
+	rejected *prometheus.CounterVec
+}
+
+// NewCirculation registers the circulation metrics. issuedCopies is queried
+// at scrape time for the number of copies currently on loan; failures are
+// logged to logger.
+func NewCirculation(r prometheus.Registerer, logger *slog.Logger, issuedCopies func() (int64, error)) *Circulation {
+	factory := promauto.With(r)
+	m := &Circulation{
+		issued: factory.NewCounter(prometheus.CounterOpts{
+			Name: "library_books_issued_total",
+			Help: "Copies issued to members.",
+		}),
+		returned: factory.NewCounter(prometheus.CounterOpts{
+			Name: "library_books_returned_total",
+			Help: "Copies returned by members.",
+		}),
+		rejected: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "library_issue_rejections_total",
+			Help: "Issue requests refused, by reason: unavailable (no copy free) or fines_owed.",
+		}, []string{"reason"}),
+	}
+	// Report zero rather than nothing until the first rejection
+	for _, reason := range []string{service.RejectUnavailable, service.RejectFinesOwed} {
+		m.rejected.WithLabelValues(reason)
+	}
+
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "library_copies_issued",
+		Help: "Copies currently on loan across all books.",
+	}, func() float64 {
+		n, err := issuedCopies()
+		if err != nil {
+			logger.Error("failed to count issued copies for metrics", "error", err)
+			return math.NaN()
+		}
+		return float64(n)
+	})
+	return m
+}
+
+func (m *Circulation) BookIssued()   { m.issued.Inc() }
+func (m *Circulation) BookReturned() { m.returned.Inc() }
+
+func (m *Circulation) IssueRejected(reason string) { m.rejected.WithLabelValues(reason).Inc() }
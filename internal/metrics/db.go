@@ -0,0 +1,17 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// dbName is the db_name label of the connection pool series
+const dbName = "library"
+
+// RegisterDBStats exposes the connection pool statistics of db as the
+// go_sql_* series
+func RegisterDBStats(r prometheus.Registerer, db *sql.DB) {
+	r.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/logging"
+	"github.com/library-api/internal/metrics"
+	"github.com/library-api/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(t *testing.T, g prometheus.Gatherer) string {
+	w := httptest.NewRecorder()
+	metrics.Handler(g).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestNewRegistry(t *testing.T) {
+	body := scrape(t, metrics.NewRegistry())
+	assert.Contains(t, body, "go_goroutines ")
+	assert.Contains(t, body, "process_start_time_seconds ")
+}
+
+func TestHTTP_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := prometheus.NewRegistry()
+	r := gin.New()
+	r.Use(metrics.NewHTTP(registry).Middleware())
+	r.GET("/books/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for _, path := range []string{"/books/1", "/books/2", "/nope"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	body := scrape(t, registry)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/books/:id",status="204"} 2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/books/:id"} 2`)
+}
+
+func TestHTTP_MiddlewareCountsPanics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := prometheus.NewRegistry()
+	r := gin.New()
+	// As in main, recovery wraps the metrics middleware
+	r.Use(logging.Recovery(logging.Discard(), func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}), metrics.NewHTTP(registry).Middleware())
+	r.GET("/boom", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/boom", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	assert.Contains(t, scrape(t, registry), `http_requests_total{method="GET",route="/boom",status="500"} 1`)
+}
+
+func TestRegisterDBStats(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics.RegisterDBStats(registry, &sql.DB{})
+
+	assert.Contains(t, scrape(t, registry), `go_sql_open_connections{db_name="library"} 0`)
+}
+
+func TestCirculation(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	var countErr error
+	m := metrics.NewCirculation(registry, logging.Discard(), func() (int64, error) { return 7, countErr })
+
+	var recorder service.CirculationRecorder = m
+	recorder.BookIssued()
+	recorder.BookIssued()
+	recorder.BookReturned()
+	recorder.IssueRejected(service.RejectUnavailable)
+
+	body := scrape(t, registry)
+	assert.Contains(t, body, "library_books_issued_total 2\n")
+	assert.Contains(t, body, "library_books_returned_total 1\n")
+	assert.Contains(t, body, `library_issue_rejections_total{reason="unavailable"} 1`)
+	assert.Contains(t, body, `library_issue_rejections_total{reason="fines_owed"} 0`)
+	assert.Contains(t, body, "library_copies_issued 7\n")
+
+	countErr = errors.New("database unavailable")
+	assert.Contains(t, scrape(t, registry), "library_copies_issued NaN\n")
+}
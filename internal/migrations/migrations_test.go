@@ -0,0 +1,248 @@
+package migrations_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/migrations"
+	"github.com/library-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestLoad(t *testing.T) {
+	all, err := migrations.Load()
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+
+	for i, m := range all {
+		assert.Equal(t, int64(i+1), m.Version, "versions must be contiguous")
+		assert.NotEmpty(t, m.Up)
+		assert.NotEmpty(t, m.Down)
+	}
+}
+
+func setupTestDB(t *testing.T) (*gorm.DB, *migrations.Migrator, []migrations.Migration) {
+	t.Helper()
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		dsn = "host=localhost user=postgres password=postgres dbname=library_test port=5434 sslmode=disable"
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	all, err := migrations.Load()
+	require.NoError(t, err)
+
+	// Start from an empty schema
+	_, err = migrator.Down(len(all))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrator().DropTable("schema_migrations", &models.APIKey{}, &models.FineEntry{}, &models.Fine{}, &models.Hold{}, &models.Loan{}, &models.Member{}, &models.Book{}, &models.Author{}, &models.Publisher{}))
+	return db, migrator, all
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	db, migrator, all := setupTestDB(t)
+
+	// Before the first run everything is pending, and checking creates nothing
+	pending, err := migrator.Pending(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, pending, len(all))
+	assert.False(t, db.Migrator().HasTable("schema_migrations"))
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(all))
+
+	// Running again is a no-op
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	pending, err = migrator.Pending(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// Every model field must have a column in the migrated schema
+	for _, model := range []interface{}{&models.Author{}, &models.Publisher{}, &models.Book{}, &models.Member{}, &models.Loan{}, &models.Hold{}, &models.Fine{}, &models.FineEntry{}, &models.APIKey{}} {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			assert.True(t, db.Migrator().HasColumn(model, field.DBName), "%s.%s has no column", stmt.Schema.Table, field.DBName)
+		}
+	}
+	assert.True(t, db.Migrator().HasConstraint(&models.Book{}, "chk_quantity_issued_valid"))
+	assert.True(t, db.Migrator().HasColumn(&models.Book{}, "search_vector"))
+
+	// Roll back the three latest migrations and re-apply them
+	rolledBack, err := migrator.Down(3)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, 3)
+	assert.Equal(t, all[len(all)-1].Version, rolledBack[0].Version)
+	assert.Equal(t, "legacy_issued_copies", rolledBack[2].Name)
+	assert.False(t, db.Migrator().HasColumn(&models.Member{}, "subject"))
+	assert.False(t, db.Migrator().HasColumn(&models.Book{}, "legacy_issued"))
+
+	pending, err = migrator.Pending(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, pending, 3)
+
+	// Copies counted before the loan ledger, with no loan row, are carried
+	// over as legacy copies; copies with an open loan are not
+	author := map[string]interface{}{"id": uuid.New(), "name": "Author"}
+	publisher := map[string]interface{}{"id": uuid.New(), "name": "Publisher"}
+	require.NoError(t, db.Table("authors").Create(author).Error)
+	require.NoError(t, db.Table("publishers").Create(publisher).Error)
+	bookID, memberID := uuid.New(), uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO books (id, title, isbn, author_id, publisher_id, year, genre, quantity, quantity_issued)
+		VALUES (?, 'Legacy', '9780000000002', ?, ?, 1990, 'Test', 3, 2)`, bookID, author["id"], publisher["id"]).Error)
+	require.NoError(t, db.Exec(`INSERT INTO members (id, name, email) VALUES (?, 'alice', 'alice@example.com')`, memberID).Error)
+	require.NoError(t, db.Exec(`INSERT INTO loans (id, book_id, member_id, issued_at, due_at) VALUES (?, ?, ?, now(), now())`,
+		uuid.New(), bookID, memberID).Error)
+
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+	var legacy int64
+	require.NoError(t, db.Raw("SELECT legacy_issued FROM books WHERE id = ?", bookID).Scan(&legacy).Error)
+	assert.Equal(t, int64(1), legacy)
+
+	// A book with loans can no longer be deleted
+	assert.Error(t, db.Exec("DELETE FROM books WHERE id = ?", bookID).Error)
+
+	// Rolling back everything leaves no application tables behind
+	_, err = migrator.Down(len(all))
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable(&models.Book{}))
+
+	_, err = migrator.Up()
+	require.NoError(t, err)
+}
+
+// The tables below mirror the models as the former AutoMigrate start-up step
+// first created them: the baseline catalogue, and members and loans from
+// before loans had due dates, renewals or overdue flags.
+
+type legacyAuthor struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primary_key"`
+	Name      string       `gorm:"type:varchar(255);not null;index"`
+	Biography string       `gorm:"type:text"`
+	Books     []legacyBook `gorm:"foreignKey:AuthorID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (legacyAuthor) TableName() string { return "authors" }
+
+type legacyPublisher struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primary_key"`
+	Name      string       `gorm:"type:varchar(255);not null;unique;index"`
+	Location  string       `gorm:"type:varchar(255)"`
+	Books     []legacyBook `gorm:"foreignKey:PublisherID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (legacyPublisher) TableName() string { return "publishers" }
+
+type legacyBook struct {
+	ID             uuid.UUID       `gorm:"type:uuid;primary_key"`
+	Title          string          `gorm:"type:varchar(255);not null;index"`
+	ISBN           string          `gorm:"type:varchar(13);unique;not null;index"`
+	AuthorID       uuid.UUID       `gorm:"type:uuid;not null"`
+	Author         legacyAuthor    `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	PublisherID    uuid.UUID       `gorm:"type:uuid;not null"`
+	Publisher      legacyPublisher `gorm:"foreignKey:PublisherID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Year           int             `gorm:"not null"`
+	Genre          string          `gorm:"type:varchar(100);not null;index"`
+	Quantity       int             `gorm:"not null"`
+	QuantityIssued int             `gorm:"not null;default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (legacyBook) TableName() string { return "books" }
+
+type legacyMember struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primary_key"`
+	Name      string       `gorm:"type:varchar(255);not null;index"`
+	Email     string       `gorm:"type:varchar(255);not null;unique;index"`
+	Phone     string       `gorm:"type:varchar(50)"`
+	Loans     []legacyLoan `gorm:"foreignKey:MemberID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (legacyMember) TableName() string { return "members" }
+
+type legacyLoan struct {
+	ID         uuid.UUID     `gorm:"type:uuid;primary_key"`
+	BookID     uuid.UUID     `gorm:"type:uuid;not null;index"`
+	Book       *legacyBook   `gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	MemberID   uuid.UUID     `gorm:"type:uuid;not null;index"`
+	Member     *legacyMember `gorm:"foreignKey:MemberID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	IssuedAt   time.Time     `gorm:"not null"`
+	ReturnedAt *time.Time    `gorm:"index"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (legacyLoan) TableName() string { return "loans" }
+
+func TestMigrator_AdoptsAutoMigrateSchema(t *testing.T) {
+	db, migrator, all := setupTestDB(t)
+
+	require.NoError(t, db.AutoMigrate(&legacyAuthor{}, &legacyPublisher{}, &legacyBook{}, &legacyMember{}, &legacyLoan{}))
+	require.NoError(t, db.Exec(`ALTER TABLE books
+		ADD CONSTRAINT chk_quantity_issued_valid
+		CHECK (quantity_issued >= 0 AND quantity_issued <= quantity)`).Error)
+
+	author := legacyAuthor{ID: uuid.New(), Name: "Author"}
+	publisher := legacyPublisher{ID: uuid.New(), Name: "Publisher"}
+	book := legacyBook{ID: uuid.New(), Title: "Legacy", ISBN: "9780000000003", AuthorID: author.ID, PublisherID: publisher.ID, Year: 1990, Genre: "Test", Quantity: 2, QuantityIssued: 1}
+	member := legacyMember{ID: uuid.New(), Name: "alice", Email: "alice@example.com"}
+	issuedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	loan := legacyLoan{ID: uuid.New(), BookID: book.ID, MemberID: member.ID, IssuedAt: issuedAt}
+	for _, row := range []interface{}{&author, &publisher, &book, &member, &loan} {
+		require.NoError(t, db.Omit(clause.Associations).Create(row).Error)
+	}
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(all))
+
+	for _, model := range []interface{}{&models.Publisher{}, &models.Book{}, &models.Loan{}} {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			assert.True(t, db.Migrator().HasColumn(model, field.DBName), "%s.%s has no column", stmt.Schema.Table, field.DBName)
+		}
+	}
+
+	// Existing rows survive, and loans without a due date get the default period
+	var adopted models.Loan
+	require.NoError(t, db.First(&adopted, "id = ?", loan.ID).Error)
+	assert.True(t, issuedAt.AddDate(0, 0, 14).Equal(adopted.DueAt), "due_at = %s", adopted.DueAt)
+	assert.False(t, adopted.Overdue)
+	assert.Zero(t, adopted.Renewals)
+
+	var count int64
+	require.NoError(t, db.Table("books").Where("id = ?", book.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS fine_entries;
+DROP TABLE IF EXISTS fines;
+DROP TABLE IF EXISTS holds;
+DROP TABLE IF EXISTS loans;
+DROP TABLE IF EXISTS members;
+DROP TABLE IF EXISTS books;
+DROP TABLE IF EXISTS publishers;
+DROP TABLE IF EXISTS authors;
@@ -0,0 +1,148 @@
+-- Baseline schema. Statements are guarded with IF NOT EXISTS so databases
+-- created by the former AutoMigrate start-up step are adopted as-is; columns
+-- added to a table after it was first created are added here too, before
+-- anything indexes them.
+
+CREATE TABLE IF NOT EXISTS authors (
+    id uuid PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    biography text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_authors_name ON authors (name);
+
+CREATE TABLE IF NOT EXISTS publishers (
+    id uuid PRIMARY KEY,
+    name varchar(255) NOT NULL UNIQUE,
+    location varchar(255),
+    parent_id uuid REFERENCES publishers (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+ALTER TABLE publishers
+    ADD COLUMN IF NOT EXISTS parent_id uuid REFERENCES publishers (id) ON UPDATE CASCADE ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_publishers_name ON publishers (name);
+CREATE INDEX IF NOT EXISTS idx_publishers_parent_id ON publishers (parent_id);
+
+CREATE TABLE IF NOT EXISTS books (
+    id uuid PRIMARY KEY,
+    title varchar(255) NOT NULL,
+    isbn varchar(13) NOT NULL UNIQUE,
+    author_id uuid NOT NULL REFERENCES authors (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    publisher_id uuid NOT NULL REFERENCES publishers (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    year bigint NOT NULL,
+    genre varchar(100) NOT NULL,
+    quantity bigint NOT NULL,
+    quantity_issued bigint NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_books_title ON books (title);
+CREATE INDEX IF NOT EXISTS idx_books_isbn ON books (isbn);
+CREATE INDEX IF NOT EXISTS idx_books_genre ON books (genre);
+-- (sort key, id) pairs back keyset pagination of the listing
+CREATE INDEX IF NOT EXISTS idx_books_title_id ON books (title, id);
+CREATE INDEX IF NOT EXISTS idx_books_year_id ON books (year, id);
+CREATE INDEX IF NOT EXISTS idx_books_created_at_id ON books (created_at, id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_quantity_issued_valid') THEN
+        ALTER TABLE books
+            ADD CONSTRAINT chk_quantity_issued_valid
+            CHECK (quantity_issued >= 0 AND quantity_issued <= quantity);
+    END IF;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS members (
+    id uuid PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    email varchar(255) NOT NULL UNIQUE,
+    phone varchar(50),
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_members_name ON members (name);
+CREATE INDEX IF NOT EXISTS idx_members_email ON members (email);
+
+CREATE TABLE IF NOT EXISTS loans (
+    id uuid PRIMARY KEY,
+    book_id uuid NOT NULL REFERENCES books (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    member_id uuid NOT NULL REFERENCES members (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    issued_at timestamptz NOT NULL,
+    due_at timestamptz NOT NULL,
+    renewals bigint NOT NULL DEFAULT 0,
+    returned_at timestamptz,
+    overdue boolean NOT NULL DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+-- Loans recorded before due dates existed get the default 14-day period
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS due_at timestamptz;
+UPDATE loans SET due_at = issued_at + interval '14 days' WHERE due_at IS NULL;
+ALTER TABLE loans ALTER COLUMN due_at SET NOT NULL;
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS renewals bigint NOT NULL DEFAULT 0;
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS overdue boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_loans_book_id ON loans (book_id);
+CREATE INDEX IF NOT EXISTS idx_loans_member_id ON loans (member_id);
+CREATE INDEX IF NOT EXISTS idx_loans_due_at ON loans (due_at);
+CREATE INDEX IF NOT EXISTS idx_loans_returned_at ON loans (returned_at);
+CREATE INDEX IF NOT EXISTS idx_loans_overdue ON loans (overdue);
+
+CREATE TABLE IF NOT EXISTS holds (
+    id uuid PRIMARY KEY,
+    book_id uuid NOT NULL REFERENCES books (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    member_id uuid NOT NULL REFERENCES members (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    status varchar(20) NOT NULL,
+    ready_at timestamptz,
+    expires_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_holds_book_id ON holds (book_id);
+CREATE INDEX IF NOT EXISTS idx_holds_member_id ON holds (member_id);
+CREATE INDEX IF NOT EXISTS idx_holds_status ON holds (status);
+CREATE INDEX IF NOT EXISTS idx_holds_expires_at ON holds (expires_at);
+
+CREATE TABLE IF NOT EXISTS fines (
+    id uuid PRIMARY KEY,
+    loan_id uuid NOT NULL UNIQUE REFERENCES loans (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    member_id uuid NOT NULL,
+    amount_cents bigint NOT NULL,
+    paid_cents bigint NOT NULL,
+    waived_cents bigint NOT NULL,
+    balance_cents bigint NOT NULL,
+    accruing boolean NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_fines_member_id ON fines (member_id);
+CREATE INDEX IF NOT EXISTS idx_fines_balance_cents ON fines (balance_cents);
+CREATE INDEX IF NOT EXISTS idx_fines_accruing ON fines (accruing);
+
+CREATE TABLE IF NOT EXISTS fine_entries (
+    id uuid PRIMARY KEY,
+    fine_id uuid NOT NULL REFERENCES fines (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    kind varchar(20) NOT NULL,
+    amount_cents bigint NOT NULL,
+    note text,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_fine_entries_fine_id ON fine_entries (fine_id);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id uuid PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    prefix varchar(16) NOT NULL,
+    hash char(64) NOT NULL,
+    scopes jsonb NOT NULL,
+    created_by varchar(255),
+    expires_at timestamptz,
+    last_used_at timestamptz,
+    revoked_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
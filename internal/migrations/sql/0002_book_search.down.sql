@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS trg_publishers_search_vector ON publishers;
+DROP TRIGGER IF EXISTS trg_authors_search_vector ON authors;
+DROP TRIGGER IF EXISTS trg_books_search_vector ON books;
+DROP FUNCTION IF EXISTS books_search_vector_touch_publisher();
+DROP FUNCTION IF EXISTS books_search_vector_touch_author();
+DROP FUNCTION IF EXISTS books_search_vector_refresh();
+DROP INDEX IF EXISTS idx_books_search_vector;
+ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,2 @@
+ALTER TABLE books DROP CONSTRAINT IF EXISTS chk_legacy_issued_valid;
+ALTER TABLE books DROP COLUMN IF EXISTS legacy_issued;
@@ -0,0 +1,21 @@
+-- Copies issued before the loan ledger existed have no loan row, only a
+-- quantity_issued count. Keep the difference as legacy_issued so those
+-- copies stay unavailable until they are returned.
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS legacy_issued bigint NOT NULL DEFAULT 0;
+
+UPDATE books
+SET legacy_issued = GREATEST(quantity_issued - (
+    SELECT count(*) FROM loans
+    WHERE loans.book_id = books.id AND loans.returned_at IS NULL
+), 0);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_legacy_issued_valid') THEN
+        ALTER TABLE books
+            ADD CONSTRAINT chk_legacy_issued_valid
+            CHECK (legacy_issued >= 0 AND legacy_issued <= quantity_issued);
+    END IF;
+END
+$$;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_members_subject;
+ALTER TABLE members DROP COLUMN IF EXISTS subject;
@@ -0,0 +1,6 @@
+-- Links a member to the sub claim of their bearer tokens, so members can
+-- act on their own holds and read their own loans and fines. Unlinked
+-- members keep a NULL subject.
+
+ALTER TABLE members ADD COLUMN IF NOT EXISTS subject varchar(255);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_members_subject ON members (subject);
@@ -0,0 +1,4 @@
+ALTER TABLE loans DROP CONSTRAINT IF EXISTS fk_loans_book;
+ALTER TABLE loans
+    ADD CONSTRAINT fk_loans_book FOREIGN KEY (book_id)
+    REFERENCES books (id) ON UPDATE CASCADE ON DELETE CASCADE;
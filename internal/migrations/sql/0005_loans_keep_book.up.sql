@@ -0,0 +1,22 @@
+-- Deleting a book used to delete its loans, and with them the loans' fines
+-- and payments. Loans now keep their book, and through it its author and
+-- publisher, from being deleted.
+
+DO $$
+DECLARE
+    fk name;
+BEGIN
+    FOR fk IN
+        SELECT c.conname FROM pg_constraint c
+        JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = ANY (c.conkey)
+        WHERE c.conrelid = 'loans'::regclass AND c.contype = 'f'
+            AND c.confrelid = 'books'::regclass AND a.attname = 'book_id'
+    LOOP
+        EXECUTE format('ALTER TABLE loans DROP CONSTRAINT %I', fk);
+    END LOOP;
+END
+$$;
+
+ALTER TABLE loans
+    ADD CONSTRAINT fk_loans_book FOREIGN KEY (book_id)
+    REFERENCES books (id) ON UPDATE CASCADE ON DELETE RESTRICT;
@@ -7,21 +7,25 @@ import (
 	"gorm.io/gorm"
 )
 
-// Book represents a book in the library
+// Book represents a book in the library. The (sort key, id) indexes back
+// keyset pagination of the listing.
 type Book struct {
-	ID             uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	Title          string    `gorm:"type:varchar(255);not null;index" json:"title" validate:"required"`
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;index:idx_books_title_id,priority:2;index:idx_books_year_id,priority:2;index:idx_books_created_at_id,priority:2" json:"id"`
+	Title          string    `gorm:"type:varchar(255);not null;index;index:idx_books_title_id,priority:1" json:"title" validate:"required"`
 	ISBN           string    `gorm:"type:varchar(13);unique;not null;index" json:"isbn" validate:"required,len=13"`
 	AuthorID       uuid.UUID `gorm:"type:uuid;not null" json:"author_id"`
 	Author         Author    `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"author"`
 	PublisherID    uuid.UUID `gorm:"type:uuid;not null" json:"publisher_id"`
 	Publisher      Publisher `gorm:"foreignKey:PublisherID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"publisher"`
-	Year           int       `gorm:"not null" json:"year" validate:"required,min=1000,max=9999"`
+	Year           int       `gorm:"not null;index:idx_books_year_id,priority:1" json:"year" validate:"required,min=1000,max=9999"`
 	Genre          string    `gorm:"type:varchar(100);not null;index" json:"genre" validate:"required"`
 	Quantity       int       `gorm:"not null" json:"quantity" validate:"required,min=0"`
 	QuantityIssued int       `gorm:"not null;default:0" json:"quantity_issued" validate:"min=0"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	// LegacyIssued counts copies issued before loans were recorded; they are
+	// part of QuantityIssued but have no open loan
+	LegacyIssued int       `gorm:"not null;default:0" json:"-"`
+	CreatedAt    time.Time `gorm:"index:idx_books_created_at_id,priority:1" json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // BeforeCreate will set a UUID rather than numeric ID
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Fine ledger entry kinds
+const (
+	FineEntryAccrual = "accrual"
+	FineEntryPayment = "payment"
+	FineEntryWaiver  = "waiver"
+)
+
+// Fine is the late fee owed on an overdue loan. Amounts are in cents; the
+// balance is what remains after payments and waivers. A fine keeps accruing
+// until the loan is returned or the cap is reached.
+type Fine struct {
+	ID           uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
+	LoanID       uuid.UUID   `gorm:"type:uuid;not null;unique" json:"loan_id"`
+	Loan         *Loan       `gorm:"foreignKey:LoanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"loan,omitempty"`
+	MemberID     uuid.UUID   `gorm:"type:uuid;not null;index" json:"member_id"`
+	AmountCents  int64       `gorm:"not null" json:"amount_cents"`
+	PaidCents    int64       `gorm:"not null" json:"paid_cents"`
+	WaivedCents  int64       `gorm:"not null" json:"waived_cents"`
+	BalanceCents int64       `gorm:"not null;index" json:"balance_cents"`
+	Accruing     bool        `gorm:"not null;index" json:"accruing"`
+	Entries      []FineEntry `gorm:"foreignKey:FineID" json:"entries,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (f *Fine) BeforeCreate(tx *gorm.DB) error {
+	f.ID = uuid.New()
+	return nil
+}
+
+// FineEntry is one immutable line of a fine's ledger
+type FineEntry struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	FineID      uuid.UUID `gorm:"type:uuid;not null;index" json:"fine_id"`
+	Fine        *Fine     `gorm:"foreignKey:FineID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"fine,omitempty"`
+	Kind        string    `gorm:"type:varchar(20);not null" json:"kind"`
+	AmountCents int64     `gorm:"not null" json:"amount_cents"`
+	Note        string    `gorm:"type:text" json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (e *FineEntry) BeforeCreate(tx *gorm.DB) error {
+	e.ID = uuid.New()
+	return nil
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Hold lifecycle states
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusCancelled = "cancelled"
+	HoldStatusExpired   = "expired"
+)
+
+// Hold is a member's place in the queue for a book with no available copies.
+// Waiting holds are served first-come first-served; a ready hold has a copy
+// set aside for the member until ExpiresAt.
+type Hold struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	BookID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"book_id"`
+	Book      *Book      `gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"book,omitempty"`
+	MemberID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"member_id"`
+	Member    *Member    `gorm:"foreignKey:MemberID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"member,omitempty"`
+	Status    string     `gorm:"type:varchar(20);not null;index" json:"status"`
+	ReadyAt   *time.Time `json:"ready_at,omitempty"`
+	ExpiresAt *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (h *Hold) BeforeCreate(tx *gorm.DB) error {
+	h.ID = uuid.New()
+	return nil
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Loan records a single copy of a book issued to a member. A loan is open
+// until ReturnedAt is set; the number of open loans for a book, plus any
+// copies issued before loans were recorded, is its QuantityIssued.
+type Loan struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	BookID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"book_id"`
+	Book       *Book      `gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"book,omitempty"`
+	MemberID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"member_id"`
+	Member     *Member    `gorm:"foreignKey:MemberID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"member,omitempty"`
+	IssuedAt   time.Time  `gorm:"not null" json:"issued_at"`
+	DueAt      time.Time  `gorm:"not null;index" json:"due_at"`
+	Renewals   int        `gorm:"not null;default:0" json:"renewals"`
+	ReturnedAt *time.Time `gorm:"index" json:"returned_at,omitempty"`
+	Overdue    bool       `gorm:"not null;default:false;index" json:"overdue"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (l *Loan) BeforeCreate(tx *gorm.DB) error {
+	l.ID = uuid.New()
+	return nil
+}
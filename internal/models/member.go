@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Member represents a library patron who can borrow books
+type Member struct {
+	ID    uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	Name  string    `gorm:"type:varchar(255);not null;index" json:"name" validate:"required"`
+	Email string    `gorm:"type:varchar(255);not null;unique;index" json:"email" validate:"required,email"`
+	Phone string    `gorm:"type:varchar(50)" json:"phone"`
+	// Subject is the sub claim of the member's bearer tokens, linking them
+	// to this record; nil for members who cannot sign in
+	Subject   *string   `gorm:"type:varchar(255);uniqueIndex" json:"subject,omitempty"`
+	Loans     []Loan    `gorm:"foreignKey:MemberID" json:"loans,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (m *Member) BeforeCreate(tx *gorm.DB) error {
+	m.ID = uuid.New()
+	return nil
+}
@@ -7,14 +7,18 @@ import (
 	"gorm.io/gorm"
 )
 
-// Publisher represents a book publisher
+// Publisher represents a book publisher. A publisher may be an imprint of a
+// parent publisher, forming a group hierarchy.
 type Publisher struct {
-	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	Name      string    `gorm:"type:varchar(255);not null;unique;index" json:"name" validate:"required"`
-	Location  string    `gorm:"type:varchar(255)" json:"location"`
-	Books     []Book    `gorm:"foreignKey:PublisherID" json:"books,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
+	Name      string      `gorm:"type:varchar(255);not null;unique;index" json:"name" validate:"required"`
+	Location  string      `gorm:"type:varchar(255)" json:"location"`
+	ParentID  *uuid.UUID  `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Parent    *Publisher  `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"parent,omitempty"`
+	Imprints  []Publisher `gorm:"foreignKey:ParentID" json:"imprints,omitempty"`
+	Books     []Book      `gorm:"foreignKey:PublisherID" json:"books,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
 }
 
 // BeforeCreate will set a UUID rather than numeric ID
@@ -0,0 +1,60 @@
+package openapi_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/library-api/internal/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generate(t *testing.T) []byte {
+	t.Helper()
+	spec, err := openapi.Generate("../..")
+	require.NoError(t, err)
+	return spec
+}
+
+func TestGenerate_UpToDate(t *testing.T) {
+	committed, err := os.ReadFile("../../internal/api/docs/openapi.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(committed), string(generate(t)),
+		"internal/api/docs/openapi.json is stale; run go generate ./internal/api")
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData(generate(t))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(openapi3.NewLoader().Context), "a valid OpenAPI 3 document")
+
+	assert.Equal(t, "Library API", doc.Info.Title)
+	apiKey := doc.Components.SecuritySchemes["ApiKeyAuth"].Value
+	assert.Equal(t, "header", apiKey.In)
+	assert.Equal(t, "X-API-Key", apiKey.Name)
+	bearer := doc.Components.SecuritySchemes["BearerAuth"].Value
+	assert.Equal(t, "http", bearer.Type)
+	assert.Equal(t, "bearer", bearer.Scheme)
+
+	getBook := doc.Paths.Find("/api/v1/books/{id}").Get
+	require.NotNil(t, getBook)
+	assert.Equal(t, openapi3.SecurityRequirements{{"BearerAuth": {}}, {"ApiKeyAuth": {}}}, *getBook.Security, "either credential works")
+	require.Len(t, getBook.Parameters, 1)
+	assert.True(t, getBook.Parameters[0].Value.Required)
+	assert.Equal(t, "#/components/schemas/models.Book",
+		getBook.Responses.Status(200).Value.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/problem.Details",
+		getBook.Responses.Status(404).Value.Content["application/json"].Schema.Ref)
+	assert.NotNil(t, getBook.Responses.Status(401), "secured operations document the auth failures")
+
+	create := doc.Paths.Find("/api/v1/books").Post.RequestBody.Value
+	assert.Equal(t, "#/components/schemas/api.CreateBookRequest", create.Content["application/json"].Schema.Ref)
+	request := doc.Components.Schemas["api.CreateBookRequest"].Value
+	assert.Contains(t, request.Required, "title")
+	assert.True(t, request.Properties["author_id"].Value.Type.Is("string"))
+
+	assert.Nil(t, doc.Paths.Find("/healthz").Get.Security)
+	assert.Contains(t, doc.Paths.Find("/metrics").Get.Responses.Status(200).Value.Content, "text/plain")
+}
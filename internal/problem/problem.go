@@ -0,0 +1,83 @@
+// Package problem writes error responses as RFC 7807 problem details.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/logging"
+)
+
+// ContentType is the media type of every error response
+const ContentType = "application/problem+json"
+
+// Problem types beyond the RFC's "about:blank", which means the status alone
+// describes the problem
+const (
+	TypeDefault = "about:blank"
+	// TypeValidation problems list the offending fields in Errors
+	TypeValidation = "/problems/validation-error"
+)
+
+// Details is an RFC 7807 problem details object
+type Details struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Errors is an extension member with one entry per invalid field
+	Errors []FieldError `json:"errors,omitempty"`
+	// RequestID is an extension member matching the X-Request-ID header, to
+	// quote when reporting the problem
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// FieldError describes one invalid input field by its JSON or query name
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// New returns the problem for status with the given detail. The instance and
+// request ID are filled in when the problem is written.
+func New(status int, detail string) *Details {
+	return &Details{
+		Type:   TypeDefault,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Validation returns a 400 problem listing the invalid fields
+func Validation(detail string, errs []FieldError) *Details {
+	p := New(http.StatusBadRequest, detail)
+	p.Type = TypeValidation
+	p.Title = "Your request parameters didn't validate"
+	p.Errors = errs
+	return p
+}
+
+// Write sends p as the response
+func Write(c *gin.Context, p *Details) {
+	if p.Instance == "" {
+		p.Instance = c.Request.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = logging.RequestIDFromContext(c.Request.Context())
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(p.Status, ContentType, body)
+}
+
+// Abort sends p and stops the handler chain, for use in middleware
+func Abort(c *gin.Context, p *Details) {
+	c.Abort()
+	Write(c, p)
+}
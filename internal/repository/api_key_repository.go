@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	List(ctx context.Context, page, limit int) ([]models.APIKey, int64, error)
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) (*models.APIKey, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time, every time.Duration) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return translateError(r.db.WithContext(ctx).Create(key).Error, "API key")
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.WithContext(ctx).First(&key, id).Error
+	if err != nil {
+		return nil, translateError(err, "API key")
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		return nil, translateError(err, "API key")
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context, page, limit int) ([]models.APIKey, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var keys []models.APIKey
+	var total int64
+
+	offset := (page - 1) * limit
+
+	err := db.Model(&models.APIKey{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, translateError(err, "API key")
+	}
+
+	err = db.Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&keys).Error
+	if err != nil {
+		return nil, 0, translateError(err, "API key")
+	}
+
+	return keys, total, nil
+}
+
+// Revoke marks the key revoked at the given time. Revoking twice keeps the
+// original time.
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) (*models.APIKey, error) {
+	err := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+	if err != nil {
+		return nil, translateError(err, "API key")
+	}
+	return r.GetByID(ctx, id)
+}
+
+// TouchLastUsed records a use of the key, writing at most once per every so
+// busy keys do not cost an UPDATE per request.
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time, every time.Duration) error {
+	err := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-every)).
+		UpdateColumn("last_used_at", at).Error
+	return translateError(err, "API key")
+}
@@ -0,0 +1,71 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	repo := repository.NewAPIKeyRepository(db)
+
+	key := &models.APIKey{
+		Name:   "Kiosk",
+		Prefix: "abc123",
+		Hash:   "0000000000000000000000000000000000000000000000000000000000000000",
+		Scopes: models.StringList{"catalog:read", "holds:manage"},
+	}
+	err := repo.Create(ctx, key)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, key.ID)
+
+	// Prefixes are unique
+	err = repo.Create(ctx, &models.APIKey{Name: "Duplicate", Prefix: "abc123", Hash: key.Hash})
+	assert.Error(t, err)
+
+	found, err := repo.GetByPrefix(ctx, "abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, key.ID, found.ID)
+	assert.Equal(t, models.StringList{"catalog:read", "holds:manage"}, found.Scopes)
+	assert.Nil(t, found.LastUsedAt)
+
+	_, err = repo.GetByPrefix(ctx, "missing")
+	assert.Error(t, err)
+
+	// last_used_at is written at most once per interval
+	first := time.Now()
+	assert.NoError(t, repo.TouchLastUsed(ctx, key.ID, first, time.Minute))
+	assert.NoError(t, repo.TouchLastUsed(ctx, key.ID, first.Add(time.Second), time.Minute))
+	found, err = repo.GetByID(ctx, key.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, found.LastUsedAt) {
+		assert.WithinDuration(t, first, *found.LastUsedAt, time.Millisecond)
+	}
+
+	assert.NoError(t, repo.TouchLastUsed(ctx, key.ID, first.Add(2*time.Minute), time.Minute))
+	found, err = repo.GetByID(ctx, key.ID)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, first.Add(2*time.Minute), *found.LastUsedAt, time.Millisecond)
+
+	// Revoking twice keeps the first revocation time
+	revokedAt := time.Now()
+	revoked, err := repo.Revoke(ctx, key.ID, revokedAt)
+	assert.NoError(t, err)
+	assert.False(t, revoked.Active(time.Now()))
+
+	revoked, err = repo.Revoke(ctx, key.ID, revokedAt.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.WithinDuration(t, revokedAt, *revoked.RevokedAt, time.Millisecond)
+
+	keys, total, err := repo.List(ctx, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, 1, len(keys))
+}
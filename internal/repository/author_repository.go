@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type AuthorRepository interface {
+	Create(ctx context.Context, author *models.Author) error
+	Update(ctx context.Context, author *models.Author) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Author, error)
+	List(ctx context.Context, page, limit int) ([]models.Author, int64, error)
+	ListBooks(ctx context.Context, id uuid.UUID, page, limit int) ([]models.Book, int64, error)
+}
+
+type authorRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthorRepository(db *gorm.DB) AuthorRepository {
+	return &authorRepository{db: db}
+}
+
+func (r *authorRepository) Create(ctx context.Context, author *models.Author) error {
+	return translateError(r.db.WithContext(ctx).Create(author).Error, "author")
+}
+
+func (r *authorRepository) Update(ctx context.Context, author *models.Author) error {
+	// Only update scalar fields to avoid touching the Books association
+	err := r.db.WithContext(ctx).Model(&models.Author{}).
+		Where("id = ?", author.ID).
+		Updates(map[string]interface{}{
+			"name":       author.Name,
+			"biography":  author.Biography,
+			"updated_at": author.UpdatedAt,
+		}).Error
+	return translateError(err, "author")
+}
+
+func (r *authorRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return deleteResult(r.db.WithContext(ctx).Delete(&models.Author{}, id), "author")
+}
+
+func (r *authorRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Author, error) {
+	var author models.Author
+	err := r.db.WithContext(ctx).First(&author, id).Error
+	if err != nil {
+		return nil, translateError(err, "author")
+	}
+	return &author, nil
+}
+
+func (r *authorRepository) List(ctx context.Context, page, limit int) ([]models.Author, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var authors []models.Author
+	var total int64
+
+	offset := (page - 1) * limit
+
+	err := db.Model(&models.Author{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, translateError(err, "author")
+	}
+
+	err = db.Offset(offset).
+		Limit(limit).
+		Find(&authors).Error
+	if err != nil {
+		return nil, 0, translateError(err, "author")
+	}
+
+	return authors, total, nil
+}
+
+func (r *authorRepository) ListBooks(ctx context.Context, id uuid.UUID, page, limit int) ([]models.Book, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var books []models.Book
+
+	offset := (page - 1) * limit
+	author := &models.Author{ID: id}
+
+	total := db.Model(author).Association("Books").Count()
+
+	err := db.Model(author).
+		Preload("Publisher").
+		Offset(offset).
+		Limit(limit).
+		Association("Books").
+		Find(&books)
+	if err != nil {
+		return nil, 0, translateError(err, "author")
+	}
+
+	return books, total, nil
+}
@@ -0,0 +1,87 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorRepository_CRUD(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	repo := repository.NewAuthorRepository(db)
+
+	// Test Create
+	author := &models.Author{
+		Name:      "Test Author",
+		Biography: "Test Biography",
+	}
+	err := repo.Create(ctx, author)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, author.ID)
+
+	// Test GetByID
+	found, err := repo.GetByID(ctx, author.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, author.Name, found.Name)
+
+	// Test Update
+	author.Name = "Updated Author"
+	err = repo.Update(ctx, author)
+	assert.NoError(t, err)
+
+	updated, err := repo.GetByID(ctx, author.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, author.Name, updated.Name)
+
+	// Test List
+	authors, total, err := repo.List(ctx, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, 1, len(authors))
+
+	// Test ListBooks through the Books association
+	publisher := &models.Publisher{Name: "Test Publisher"}
+	err = db.Create(publisher).Error
+	assert.NoError(t, err)
+
+	for _, isbn := range []string{"1234567890123", "1234567890124", "1234567890125"} {
+		book := &models.Book{
+			Title:       "Book " + isbn,
+			ISBN:        isbn,
+			AuthorID:    author.ID,
+			PublisherID: publisher.ID,
+			Year:        2025,
+			Genre:       "Test",
+			Quantity:    1,
+		}
+		err = db.Create(book).Error
+		assert.NoError(t, err)
+	}
+
+	books, total, err := repo.ListBooks(ctx, author.ID, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, 2, len(books))
+	assert.Equal(t, publisher.Name, books[0].Publisher.Name)
+
+	books, _, err = repo.ListBooks(ctx, author.ID, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(books))
+
+	// Test Delete
+	err = repo.Delete(ctx, author.ID)
+	assert.NoError(t, err)
+
+	_, err = repo.GetByID(ctx, author.ID)
+	assert.Error(t, err)
+
+	// Deleting a missing author is reported
+	err = repo.Delete(ctx, author.ID)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
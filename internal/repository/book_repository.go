@@ -1,104 +1,471 @@
 package repository
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/library-api/internal/apperr"
 	"github.com/library-api/internal/models"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// BookFilter narrows and orders a book listing. Zero values match everything.
+type BookFilter struct {
+	Genre       string
+	YearFrom    int
+	YearTo      int
+	AuthorID    uuid.UUID
+	PublisherID uuid.UUID
+	// Available, when set, keeps only books with (true) or without (false)
+	// copies on the shelf
+	Available *bool
+	// Query matches the title or author name, case-insensitively
+	Query string
+	// SortBy is one of the BookSort* columns; SortDesc reverses it
+	SortBy   string
+	SortDesc bool
+}
+
+// Sortable book columns
+const (
+	BookSortTitle     = "title"
+	BookSortYear      = "year"
+	BookSortCreatedAt = "created_at"
+)
+
+var bookSortColumns = map[string]string{
+	BookSortTitle:     "books.title",
+	BookSortYear:      "books.year",
+	BookSortCreatedAt: "books.created_at",
+}
+
+// BookCursor marks a position in a sorted book listing: the sort key and id
+// of the book the next page starts after. Clients only see it encoded.
+type BookCursor struct {
+	SortBy    string     `json:"s"`
+	SortDesc  bool       `json:"d,omitempty"`
+	Title     string     `json:"t,omitempty"`
+	Year      int        `json:"y,omitempty"`
+	CreatedAt *time.Time `json:"c,omitempty"`
+	ID        uuid.UUID  `json:"i"`
+	// Backward pages towards the start of the listing
+	Backward bool `json:"b,omitempty"`
+}
+
+// BookPage is one page of a cursor listing. Next and Prev are nil at either
+// end, and Total is only counted when asked for.
+type BookPage struct {
+	Books []models.Book
+	Next  *BookCursor
+	Prev  *BookCursor
+	Total *int64
+}
+
+func newBookCursor(sortBy string, sortDesc bool, book models.Book, backward bool) *BookCursor {
+	cursor := &BookCursor{SortBy: sortBy, SortDesc: sortDesc, ID: book.ID, Backward: backward}
+	switch sortBy {
+	case BookSortTitle:
+		cursor.Title = book.Title
+	case BookSortYear:
+		cursor.Year = book.Year
+	default:
+		createdAt := book.CreatedAt
+		cursor.CreatedAt = &createdAt
+	}
+	return cursor
+}
+
+func (c *BookCursor) sortValue() interface{} {
+	switch c.SortBy {
+	case BookSortTitle:
+		return c.Title
+	case BookSortYear:
+		return c.Year
+	default:
+		return c.CreatedAt
+	}
+}
+
+// Encode returns the cursor as an opaque URL-safe token
+func (c *BookCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeBookCursor parses a token produced by BookCursor.Encode
+func DecodeBookCursor(token string) (*BookCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, apperr.Validation("invalid cursor")
+	}
+
+	var cursor BookCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, apperr.Validation("invalid cursor")
+	}
+	if _, ok := bookSortColumns[cursor.SortBy]; !ok || cursor.ID == uuid.Nil {
+		return nil, apperr.Validation("invalid cursor")
+	}
+	if cursor.SortBy == BookSortCreatedAt && cursor.CreatedAt == nil {
+		return nil, apperr.Validation("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// BookRepository stores books and their circulation. Every method stops
+// waiting on the database once ctx is done.
 type BookRepository interface {
-	Create(book *models.Book) error
-	Update(book *models.Book) error
-	Delete(id uuid.UUID) error
-	GetByID(id uuid.UUID) (*models.Book, error)
-	List(page, limit int) ([]models.Book, int64, error)
-	IssueBook(id uuid.UUID) (*models.Book, error)
-	ReturnBook(id uuid.UUID) (*models.Book, error)
+	Create(ctx context.Context, book *models.Book) error
+	Update(ctx context.Context, book *models.Book) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Book, error)
+	GetByISBN(ctx context.Context, isbn string) (*models.Book, error)
+	List(ctx context.Context, filter BookFilter, page, limit int) ([]models.Book, int64, error)
+	ListByCursor(ctx context.Context, filter BookFilter, cursor *BookCursor, limit int, withTotal bool) (*BookPage, error)
+	// IssueBook lends a copy to a member owing at most maxFinesCents
+	IssueBook(ctx context.Context, id, memberID uuid.UUID, dueAt time.Time, maxFinesCents int64) (*models.Book, error)
+	ReturnBook(ctx context.Context, id, memberID uuid.UUID, holdPickupWindow time.Duration) (*models.Book, error)
+	RenewBook(ctx context.Context, id, memberID uuid.UUID, extension time.Duration, maxRenewals int) (*models.Loan, error)
+	// CountIssuedCopies returns how many copies are on loan across all books
+	CountIssuedCopies(ctx context.Context) (int64, error)
 }
 
+// ErrNoAvailableCopies is returned by IssueBook when every copy is on loan
+// or set aside for a hold
+var ErrNoAvailableCopies = apperr.Conflict("no available copies to issue")
+
+// ErrFinesOwed is wrapped in the conflict IssueBook returns when the member
+// owes more than the limit
+var ErrFinesOwed = errors.New("member owes too much in fines")
+
 type bookRepository struct {
-	db *gorm.DB
+	db           *gorm.DB
+	queryTimeout time.Duration
+}
+
+// NewBookRepository returns a BookRepository. A positive queryTimeout bounds
+// each call, including every query of a transaction, on top of any deadline
+// ctx already has.
+func NewBookRepository(db *gorm.DB, queryTimeout time.Duration) BookRepository {
+	return &bookRepository{db: db, queryTimeout: queryTimeout}
 }
 
-func NewBookRepository(db *gorm.DB) BookRepository {
-	return &bookRepository{db: db}
+// withContext returns the database handle for one call, bound to ctx and
+// the query timeout. Callers must call cancel when done.
+func (r *bookRepository) withContext(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	if r.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.queryTimeout)
+		return r.db.WithContext(ctx), cancel
+	}
+	return r.db.WithContext(ctx), func() {}
+}
+
+func (r *bookRepository) Create(ctx context.Context, book *models.Book) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+	return translateError(db.Create(book).Error, "book")
 }
 
-func (r *bookRepository) Create(book *models.Book) error {
-	return r.db.Create(book).Error
+func (r *bookRepository) Update(ctx context.Context, book *models.Book) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Lock the row FOR UPDATE so no copy is issued while quantity changes
+		var current models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&current, "id = ?", book.ID).Error; err != nil {
+			return err
+		}
+
+		issued, err := countIssuedCopies(tx, &current)
+		if err != nil {
+			return err
+		}
+		if int64(book.Quantity) < issued {
+			return apperr.Conflict("quantity cannot be less than the number of issued copies")
+		}
+
+		// Only update scalar fields to avoid overwriting Author/Publisher
+		// relations; issued copies are owned by the loan ledger
+		if err := tx.Model(&models.Book{}).
+			Where("id = ?", book.ID).
+			Updates(map[string]interface{}{
+				"title":        book.Title,
+				"isbn":         book.ISBN,
+				"author_id":    book.AuthorID,
+				"publisher_id": book.PublisherID,
+				"year":         book.Year,
+				"genre":        book.Genre,
+				"quantity":     book.Quantity,
+				"updated_at":   book.UpdatedAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		book.QuantityIssued = int(issued)
+		book.LegacyIssued = current.LegacyIssued
+		return nil
+	})
+	return translateError(err, "book")
 }
 
-func (r *bookRepository) Update(book *models.Book) error {
-	// Only update scalar fields to avoid overwriting Author/Publisher relations
-	return r.db.Model(&models.Book{}).
-		Where("id = ?", book.ID).
-		Updates(map[string]interface{}{
-			"title":           book.Title,
-			"isbn":            book.ISBN,
-			"author_id":       book.AuthorID,
-			"publisher_id":    book.PublisherID,
-			"year":            book.Year,
-			"genre":           book.Genre,
-			"quantity":        book.Quantity,
-			"quantity_issued": book.QuantityIssued,
-			"updated_at":      book.UpdatedAt,
-		}).Error
+func (r *bookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+	return deleteResult(db.Delete(&models.Book{}, id), "book")
 }
 
-func (r *bookRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.Book{}, id).Error
+func (r *bookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Book, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var book models.Book
+	err := db.Preload("Author").Preload("Publisher").First(&book, id).Error
+	if err != nil {
+		return nil, translateError(err, "book")
+	}
+	return &book, nil
 }
 
-func (r *bookRepository) GetByID(id uuid.UUID) (*models.Book, error) {
+func (r *bookRepository) GetByISBN(ctx context.Context, isbn string) (*models.Book, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var book models.Book
-	err := r.db.Preload("Author").Preload("Publisher").First(&book, id).Error
+	err := db.Preload("Author").Preload("Publisher").Where("isbn = ?", isbn).First(&book).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "book")
 	}
 	return &book, nil
 }
 
-func (r *bookRepository) List(page, limit int) ([]models.Book, int64, error) {
+func (r *bookRepository) List(ctx context.Context, filter BookFilter, page, limit int) ([]models.Book, int64, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var books []models.Book
 	var total int64
 
 	offset := (page - 1) * limit
 
-	err := r.db.Model(&models.Book{}).Count(&total).Error
+	err := db.Model(&models.Book{}).Scopes(filterBooks(filter)).Count(&total).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, translateError(err, "book")
+	}
+
+	column, ok := bookSortColumns[filter.SortBy]
+	if !ok {
+		column = bookSortColumns[BookSortCreatedAt]
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
 	}
 
-	err = r.db.Preload("Author").Preload("Publisher").
+	err = db.Scopes(filterBooks(filter)).
+		Preload("Author").Preload("Publisher").
+		// Tie-break on id so pages are stable when sort values repeat
+		Order(column + " " + direction + ", books.id " + direction).
 		Offset(offset).
 		Limit(limit).
 		Find(&books).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, translateError(err, "book")
 	}
 
 	return books, total, nil
 }
 
-func (r *bookRepository) IssueBook(id uuid.UUID) (*models.Book, error) {
-	var book models.Book
+// ListByCursor pages through books by seeking past the cursor's sort key
+// instead of using OFFSET, so deep pages cost the same as the first. A nil
+// cursor starts at the beginning; the cursor must use the filter's sort.
+func (r *bookRepository) ListByCursor(ctx context.Context, filter BookFilter, cursor *BookCursor, limit int, withTotal bool) (*BookPage, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	page := &BookPage{}
+
+	if withTotal {
+		var total int64
+		if err := db.Model(&models.Book{}).Scopes(filterBooks(filter)).Count(&total).Error; err != nil {
+			return nil, translateError(err, "book")
+		}
+		page.Total = &total
+	}
+
+	sortBy := filter.SortBy
+	if _, ok := bookSortColumns[sortBy]; !ok {
+		sortBy = BookSortCreatedAt
+	}
+	column := bookSortColumns[sortBy]
+
+	if cursor != nil && (cursor.SortBy != sortBy || cursor.SortDesc != filter.SortDesc) {
+		return nil, apperr.Validation("cursor does not match the requested sort order")
+	}
+
+	// Walking backwards flips both the comparison and the order
+	backward := cursor != nil && cursor.Backward
+	desc := filter.SortDesc != backward
+	op, direction := ">", "ASC"
+	if desc {
+		op, direction = "<", "DESC"
+	}
+
+	query := db.Scopes(filterBooks(filter)).Preload("Author").Preload("Publisher")
+	if cursor != nil {
+		query = query.Where(fmt.Sprintf("(%s, books.id) %s (?, ?)", column, op), cursor.sortValue(), cursor.ID)
+	}
+
+	// One extra row tells us whether another page follows
+	var books []models.Book
+	err := query.
+		Order(column + " " + direction + ", books.id " + direction).
+		Limit(limit + 1).
+		Find(&books).Error
+	if err != nil {
+		return nil, translateError(err, "book")
+	}
+
+	more := len(books) > limit
+	if more {
+		books = books[:limit]
+	}
+	if backward {
+		for i, j := 0, len(books)-1; i < j; i, j = i+1, j-1 {
+			books[i], books[j] = books[j], books[i]
+		}
+	}
+	page.Books = books
+
+	if len(books) == 0 {
+		return page, nil
+	}
 
-	err := r.db.Transaction(func(tx *gorm.DB) error {
+	// Coming from a cursor means there is something on the side we came from
+	hasNext, hasPrev := more, cursor != nil
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		page.Next = newBookCursor(sortBy, filter.SortDesc, books[len(books)-1], false)
+	}
+	if hasPrev {
+		page.Prev = newBookCursor(sortBy, filter.SortDesc, books[0], true)
+	}
+
+	return page, nil
+}
+
+// filterBooks applies a BookFilter's conditions to a books query
+func filterBooks(filter BookFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Genre != "" {
+			db = db.Where("LOWER(books.genre) = LOWER(?)", filter.Genre)
+		}
+		if filter.YearFrom != 0 {
+			db = db.Where("books.year >= ?", filter.YearFrom)
+		}
+		if filter.YearTo != 0 {
+			db = db.Where("books.year <= ?", filter.YearTo)
+		}
+		if filter.AuthorID != uuid.Nil {
+			db = db.Where("books.author_id = ?", filter.AuthorID)
+		}
+		if filter.PublisherID != uuid.Nil {
+			db = db.Where("books.publisher_id = ?", filter.PublisherID)
+		}
+		if filter.Available != nil {
+			if *filter.Available {
+				db = db.Where("books.quantity > books.quantity_issued")
+			} else {
+				db = db.Where("books.quantity <= books.quantity_issued")
+			}
+		}
+		if filter.Query != "" {
+			pattern := "%" + escapeLike(filter.Query) + "%"
+			db = db.Joins("JOIN authors ON authors.id = books.author_id").
+				Where("books.title ILIKE ? OR authors.name ILIKE ?", pattern, pattern)
+		}
+		return db
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (r *bookRepository) IssueBook(ctx context.Context, id, memberID uuid.UUID, dueAt time.Time, maxFinesCents int64) (*models.Book, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var book models.Book
+	err := db.Transaction(func(tx *gorm.DB) error {
 		// Lock the row FOR UPDATE to prevent concurrent modifications
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 			First(&book, "id = ?", id).Error; err != nil {
 			return err
 		}
 
+		var member models.Member
+		if err := tx.First(&member, "id = ?", memberID).Error; err != nil {
+			return translateError(err, "member")
+		}
+
+		balance, err := outstandingBalance(tx, memberID)
+		if err != nil {
+			return err
+		}
+		if balance > maxFinesCents {
+			return apperr.Wrap(apperr.ErrConflict, ErrFinesOwed, "member has %d cents in unpaid fines", balance)
+		}
+
+		// The loan ledger is the source of truth for issued copies
+		issued, err := countIssuedCopies(tx, &book)
+		if err != nil {
+			return err
+		}
+
+		// Copies set aside for other members' ready holds are not available
+		reserved, err := countReadyHolds(tx, book.ID, memberID)
+		if err != nil {
+			return err
+		}
+
 		// Business logic: ensure we don't issue beyond total quantity
-		if book.QuantityIssued >= book.Quantity {
-			return fmt.Errorf("no available copies to issue")
+		if issued+reserved >= int64(book.Quantity) {
+			return ErrNoAvailableCopies
+		}
+
+		// Record who holds the copy
+		loan := models.Loan{
+			BookID:   book.ID,
+			MemberID: memberID,
+			IssuedAt: time.Now(),
+			DueAt:    dueAt,
+		}
+		if err := tx.Create(&loan).Error; err != nil {
+			return err
 		}
 
-		book.QuantityIssued++
+		// The member's own hold, if any, is satisfied by this loan
+		if err := tx.Model(&models.Hold{}).
+			Where("book_id = ? AND member_id = ? AND status IN ?", book.ID, memberID, activeHoldStatuses).
+			Update("status", models.HoldStatusFulfilled).Error; err != nil {
+			return err
+		}
+
+		book.QuantityIssued = int(issued) + 1
 
 		// Save updated state
 		if err := tx.Save(&book).Error; err != nil {
@@ -109,40 +476,154 @@ func (r *bookRepository) IssueBook(id uuid.UUID) (*models.Book, error) {
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "book")
 	}
 
 	return &book, nil
 }
 
-func (r *bookRepository) ReturnBook(id uuid.UUID) (*models.Book, error) {
-	var book models.Book
+func (r *bookRepository) ReturnBook(ctx context.Context, id, memberID uuid.UUID, holdPickupWindow time.Duration) (*models.Book, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
 
-	err := r.db.Transaction(func(tx *gorm.DB) error {
+	var book models.Book
+	err := db.Transaction(func(tx *gorm.DB) error {
 		// Lock the row FOR UPDATE
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 			First(&book, "id = ?", id).Error; err != nil {
 			return err
 		}
 
+		issued, err := countIssuedCopies(tx, &book)
+		if err != nil {
+			return err
+		}
+
 		// Ensure you can't return below 0
-		if book.QuantityIssued <= 0 {
-			return fmt.Errorf("no issued copies to return")
+		if issued <= 0 {
+			return apperr.Conflict("no issued copies to return")
+		}
+
+		// Close the member's oldest open loan for this book. Copies issued
+		// before loans were recorded belong to no one, so any member may
+		// return one.
+		var loan models.Loan
+		err = tx.Where("book_id = ? AND member_id = ? AND returned_at IS NULL", id, memberID).
+			Order("issued_at").
+			First(&loan).Error
+		switch {
+		case err == nil:
+			now := time.Now()
+			loan.ReturnedAt = &now
+			if err := tx.Save(&loan).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound) && book.LegacyIssued > 0:
+			book.LegacyIssued--
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return apperr.Conflict("member has no issued copy of this book")
+		default:
+			return err
 		}
 
-		book.QuantityIssued--
+		book.QuantityIssued = int(issued) - 1
 
 		// Save updated state
 		if err := tx.Save(&book).Error; err != nil {
 			return err
 		}
 
-		return nil
+		// Set the returned copy aside for the next member in the queue
+		return promoteHolds(tx, &book, holdPickupWindow)
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "book")
 	}
 
 	return &book, nil
 }
+
+func (r *bookRepository) CountIssuedCopies(ctx context.Context) (int64, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var total int64
+	err := db.Model(&models.Book{}).Select("COALESCE(SUM(quantity_issued), 0)").Scan(&total).Error
+	return total, translateError(err, "book")
+}
+
+func (r *bookRepository) RenewBook(ctx context.Context, id, memberID uuid.UUID, extension time.Duration, maxRenewals int) (*models.Loan, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var loan models.Loan
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Lock the book so no hold can be placed while we decide
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&book, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("book_id = ? AND member_id = ? AND returned_at IS NULL", id, memberID).
+			Order("issued_at").
+			First(&loan).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperr.Conflict("member has no issued copy of this book")
+			}
+			return err
+		}
+
+		if loan.Renewals >= maxRenewals {
+			return apperr.Conflict("loan has reached the limit of %d renewals", maxRenewals)
+		}
+
+		// Someone else is waiting for this book
+		var waiting int64
+		if err := tx.Model(&models.Hold{}).
+			Where("book_id = ? AND status = ?", id, models.HoldStatusWaiting).
+			Count(&waiting).Error; err != nil {
+			return err
+		}
+		if waiting > 0 {
+			return apperr.Conflict("book has pending holds")
+		}
+
+		// Extend from the due date, or from now if the loan is already late
+		from := loan.DueAt
+		if now := time.Now(); now.After(from) {
+			from = now
+		}
+		loan.DueAt = from.Add(extension)
+		loan.Renewals++
+
+		return tx.Save(&loan).Error
+	})
+
+	if err != nil {
+		return nil, translateError(err, "book")
+	}
+
+	return &loan, nil
+}
+
+// countIssuedCopies returns the copies of a book on open loans plus those
+// issued before loans were recorded. Callers must hold the book row lock.
+func countIssuedCopies(tx *gorm.DB, book *models.Book) (int64, error) {
+	openLoans, err := countOpenLoans(tx, book.ID)
+	if err != nil {
+		return 0, err
+	}
+	return openLoans + int64(book.LegacyIssued), nil
+}
+
+// countOpenLoans returns the number of copies of a book currently on loan.
+// Callers must hold the book row lock so the count cannot change under them.
+func countOpenLoans(tx *gorm.DB, bookID uuid.UUID) (int64, error) {
+	var count int64
+	err := tx.Model(&models.Loan{}).
+		Where("book_id = ? AND returned_at IS NULL", bookID).
+		Count(&count).Error
+	return count, err
+}
@@ -1,10 +1,15 @@
 package repository_test
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/migrations"
 	"github.com/library-api/internal/models"
 	"github.com/library-api/internal/repository"
 	"github.com/stretchr/testify/assert"
@@ -23,12 +28,15 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Drop tables in reverse order to handle foreign key constraints
-	if err := db.Migrator().DropTable(&models.Book{}, &models.Author{}, &models.Publisher{}); err != nil {
+	if err := db.Migrator().DropTable("schema_migrations", &models.APIKey{}, &models.FineEntry{}, &models.Fine{}, &models.Hold{}, &models.Loan{}, &models.Member{}, &models.Book{}, &models.Author{}, &models.Publisher{}); err != nil {
 		t.Fatalf("Failed to drop tables: %v", err)
 	}
 
-	err = db.AutoMigrate(&models.Author{}, &models.Publisher{}, &models.Book{})
+	migrator, err := migrations.New(db)
 	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
 
@@ -37,7 +45,8 @@ func setupTestDB(t *testing.T) *gorm.DB {
 
 func TestBookRepository_CRUD(t *testing.T) {
 	db := setupTestDB(t)
-	repo := repository.NewBookRepository(db)
+	repo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
 
 	// Create test author and publisher
 	author := &models.Author{
@@ -53,6 +62,20 @@ func TestBookRepository_CRUD(t *testing.T) {
 	err = db.Create(publisher).Error
 	assert.NoError(t, err, "Failed to create test publisher")
 
+	member := &models.Member{
+		Name:  "Test Member",
+		Email: "member@example.com",
+	}
+	err = db.Create(member).Error
+	assert.NoError(t, err, "Failed to create test member")
+
+	otherMember := &models.Member{
+		Name:  "Other Member",
+		Email: "other@example.com",
+	}
+	err = db.Create(otherMember).Error
+	assert.NoError(t, err, "Failed to create second test member")
+
 	// Test Create
 	book := &models.Book{
 		Title:       "Test Book",
@@ -64,54 +87,102 @@ func TestBookRepository_CRUD(t *testing.T) {
 		Quantity:    2,
 	}
 
-	err = repo.Create(book)
+	err = repo.Create(ctx, book)
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, book.ID)
 
 	// Test GetByID
-	found, err := repo.GetByID(book.ID)
+	found, err := repo.GetByID(ctx, book.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, book.Title, found.Title)
 	assert.Equal(t, book.ISBN, found.ISBN)
 
+	// Test GetByISBN
+	found, err = repo.GetByISBN(ctx, book.ISBN)
+	assert.NoError(t, err)
+	assert.Equal(t, book.ID, found.ID)
+	assert.Equal(t, "Test Author", found.Author.Name)
+
 	// Test Update
 	book.Title = "Updated Test Book"
-	err = repo.Update(book)
+	err = repo.Update(ctx, book)
 	assert.NoError(t, err)
 
-	updated, err := repo.GetByID(book.ID)
+	updated, err := repo.GetByID(ctx, book.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, book.Title, updated.Title)
 
 	// Test Issue
-	_, err = repo.IssueBook(book.ID)
+	dueAt := time.Now().Add(14 * 24 * time.Hour)
+	_, err = repo.IssueBook(ctx, book.ID, member.ID, dueAt, 0)
 	assert.NoError(t, err)
-	_, err = repo.IssueBook(book.ID)
+	_, err = repo.IssueBook(ctx, book.ID, member.ID, dueAt, 0)
 	assert.NoError(t, err)
-	_, err = repo.IssueBook(book.ID)
+	_, err = repo.IssueBook(ctx, book.ID, member.ID, dueAt, 0)
 	assert.Error(t, err)
 
+	// Issuing to an unknown member must fail
+	_, err = repo.IssueBook(ctx, book.ID, uuid.New(), dueAt, 0)
+	assert.Error(t, err)
+
+	var openLoans int64
+	db.Model(&models.Loan{}).Where("book_id = ? AND returned_at IS NULL", book.ID).Count(&openLoans)
+	assert.Equal(t, int64(2), openLoans)
+
+	issued, err := repo.GetByID(ctx, book.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int(openLoans), issued.QuantityIssued)
+
+	// Quantity is checked against open loans, not the caller's stale count,
+	// and an update never writes the issued count
+	stale := *issued
+	stale.QuantityIssued = 0
+	stale.Quantity = 1
+	err = repo.Update(ctx, &stale)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	stale.Quantity = 3
+	assert.NoError(t, repo.Update(ctx, &stale))
+	assert.Equal(t, 2, stale.QuantityIssued)
+	issued, err = repo.GetByID(ctx, book.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, issued.Quantity)
+	assert.Equal(t, 2, issued.QuantityIssued)
+
 	// Test Return
-	_, err = repo.ReturnBook(book.ID)
+	_, err = repo.ReturnBook(ctx, book.ID, otherMember.ID, time.Hour)
+	assert.Error(t, err, "member without a loan must not be able to return")
+	_, err = repo.ReturnBook(ctx, book.ID, member.ID, time.Hour)
 	assert.NoError(t, err)
-	_, err = repo.ReturnBook(book.ID)
+	_, err = repo.ReturnBook(ctx, book.ID, member.ID, time.Hour)
 	assert.NoError(t, err)
-	_, err = repo.ReturnBook(book.ID)
+	_, err = repo.ReturnBook(ctx, book.ID, member.ID, time.Hour)
 	assert.Error(t, err)
 
+	db.Model(&models.Loan{}).Where("book_id = ? AND returned_at IS NULL", book.ID).Count(&openLoans)
+	assert.Equal(t, int64(0), openLoans)
+
 	// Test List
-	books, total, err := repo.List(1, 10)
+	books, total, err := repo.List(ctx, repository.BookFilter{}, 1, 10)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), total)
 	assert.Equal(t, 1, len(books))
 
+	// Returned loans still keep the book from being deleted
+	err = repo.Delete(ctx, book.ID)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	assert.NoError(t, db.Where("book_id = ?", book.ID).Delete(&models.Loan{}).Error)
+
 	// Test Delete
-	err = repo.Delete(book.ID)
+	err = repo.Delete(ctx, book.ID)
 	assert.NoError(t, err)
 
-	_, err = repo.GetByID(book.ID)
+	_, err = repo.GetByID(ctx, book.ID)
 	assert.Error(t, err)
 
+	// Deleting a missing book is reported
+	err = repo.Delete(ctx, book.ID)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
 	// Clean up author and publisher
 	err = db.Delete(publisher).Error
 	assert.NoError(t, err)
@@ -119,3 +190,309 @@ func TestBookRepository_CRUD(t *testing.T) {
 	err = db.Delete(author).Error
 	assert.NoError(t, err)
 }
+
+func TestBookRepository_RenewBook(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+	holdRepo := repository.NewHoldRepository(db)
+
+	book, members := seedSingleCopyBook(t, db, "alice", "bob")
+	alice, bob := members[0], members[1]
+	period := 14 * 24 * time.Hour
+
+	dueAt := time.Now().Add(period)
+	_, err := repo.IssueBook(ctx, book.ID, alice.ID, dueAt, 0)
+	assert.NoError(t, err)
+
+	// Only the borrower can renew
+	_, err = repo.RenewBook(ctx, book.ID, bob.ID, period, 2)
+	assert.Error(t, err)
+
+	loan, err := repo.RenewBook(ctx, book.ID, alice.ID, period, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, loan.Renewals)
+	assert.WithinDuration(t, dueAt.Add(period), loan.DueAt, time.Second)
+
+	loan, err = repo.RenewBook(ctx, book.ID, alice.ID, period, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, loan.Renewals)
+
+	_, err = repo.RenewBook(ctx, book.ID, alice.ID, period, 2)
+	assert.Error(t, err, "renewal limit must be enforced")
+
+	// A waiting hold blocks renewal even under the limit
+	_, err = holdRepo.PlaceHold(ctx, book.ID, bob.ID)
+	assert.NoError(t, err)
+
+	_, err = repo.RenewBook(ctx, book.ID, alice.ID, period, 5)
+	assert.Error(t, err, "pending holds must block renewal")
+}
+
+func TestBookRepository_LegacyIssuedCopies(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+
+	book, members := seedSingleCopyBook(t, db, "alice", "bob")
+	alice, bob := members[0], members[1]
+	dueAt := time.Now().Add(14 * 24 * time.Hour)
+
+	// A copy issued before loans were recorded, as migration 0003 leaves it
+	assert.NoError(t, db.Model(book).Updates(map[string]interface{}{"quantity_issued": 1, "legacy_issued": 1}).Error)
+
+	_, err := repo.IssueBook(ctx, book.ID, alice.ID, dueAt, 0)
+	assert.ErrorIs(t, err, repository.ErrNoAvailableCopies, "the legacy copy is still out")
+
+	// Nobody has a loan for it, so anyone can bring it back
+	returned, err := repo.ReturnBook(ctx, book.ID, bob.ID, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, returned.QuantityIssued)
+	assert.Equal(t, 0, returned.LegacyIssued)
+
+	_, err = repo.ReturnBook(ctx, book.ID, bob.ID, time.Hour)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+
+	issued, err := repo.IssueBook(ctx, book.ID, alice.ID, dueAt, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, issued.QuantityIssued)
+}
+
+func TestBookRepository_ErrorKinds(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+	memberRepo := repository.NewMemberRepository(db)
+
+	book, members := seedSingleCopyBook(t, db, "alice", "bob")
+	alice, bob := members[0], members[1]
+	dueAt := time.Now().Add(14 * 24 * time.Hour)
+
+	_, err := repo.GetByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+	assert.EqualError(t, err, "book not found")
+
+	duplicate := *book
+	duplicate.ID = uuid.Nil
+	err = repo.Create(ctx, &duplicate)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	assert.EqualError(t, err, "a book with this isbn already exists")
+
+	orphan := *book
+	orphan.ID = uuid.Nil
+	orphan.ISBN = "9999999999999"
+	orphan.AuthorID = uuid.New()
+	err = repo.Create(ctx, &orphan)
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+
+	_, err = repo.IssueBook(ctx, uuid.New(), alice.ID, dueAt, 0)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	_, err = repo.IssueBook(ctx, book.ID, uuid.New(), dueAt, 0)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+	assert.EqualError(t, err, "member not found")
+
+	_, err = repo.IssueBook(ctx, book.ID, alice.ID, dueAt, 0)
+	assert.NoError(t, err)
+
+	issued, err := repo.CountIssuedCopies(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), issued)
+
+	_, err = repo.IssueBook(ctx, book.ID, bob.ID, dueAt, 0)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	assert.ErrorIs(t, err, repository.ErrNoAvailableCopies)
+
+	_, err = repo.ReturnBook(ctx, book.ID, bob.ID, time.Hour)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+
+	// Loans keep their member from being deleted
+	err = memberRepo.Delete(ctx, alice.ID)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+
+	// ...and their book, along with its author and publisher
+	err = repo.Delete(ctx, book.ID)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	assert.EqualError(t, err, "book is still referenced by loans")
+	err = repository.NewAuthorRepository(db).Delete(ctx, book.AuthorID)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	err = repository.NewPublisherRepository(db).Delete(ctx, book.PublisherID)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+}
+
+func TestBookRepository_Context(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewBookRepository(db, 100*time.Millisecond)
+
+	book, members := seedSingleCopyBook(t, db, "alice")
+	dueAt := time.Now().Add(14 * 24 * time.Hour)
+
+	// A cancelled context stops the call before it reaches the database
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := repo.GetByID(ctx, book.ID)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Waiting on a row lock held elsewhere runs into the query timeout
+	tx := db.Begin()
+	defer tx.Rollback()
+	assert.NoError(t, tx.Exec("SELECT 1 FROM books WHERE id = ? FOR UPDATE", book.ID).Error)
+
+	start := time.Now()
+	_, err = repo.IssueBook(context.Background(), book.ID, members[0].ID, dueAt, 0)
+	assert.ErrorIs(t, err, apperr.ErrUnavailable)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestBookRepository_ListFilters(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+
+	tolkien := &models.Author{Name: "J.R.R. Tolkien"}
+	assert.NoError(t, db.Create(tolkien).Error)
+	herbert := &models.Author{Name: "Frank Herbert"}
+	assert.NoError(t, db.Create(herbert).Error)
+
+	publisher := &models.Publisher{Name: "Test Publisher"}
+	assert.NoError(t, db.Create(publisher).Error)
+
+	books := []models.Book{
+		{Title: "The Hobbit", ISBN: "9780000000001", AuthorID: tolkien.ID, Year: 1937, Genre: "Fantasy", Quantity: 1, QuantityIssued: 1},
+		{Title: "The Two Towers", ISBN: "9780000000002", AuthorID: tolkien.ID, Year: 1954, Genre: "fantasy", Quantity: 2},
+		{Title: "Dune", ISBN: "9780000000003", AuthorID: herbert.ID, Year: 1965, Genre: "Science Fiction", Quantity: 1},
+		{Title: "100%_Pure", ISBN: "9780000000004", AuthorID: herbert.ID, Year: 1970, Genre: "Essay", Quantity: 1},
+	}
+	for i := range books {
+		books[i].PublisherID = publisher.ID
+		assert.NoError(t, db.Create(&books[i]).Error)
+	}
+
+	titles := func(books []models.Book) []string {
+		var out []string
+		for _, b := range books {
+			out = append(out, b.Title)
+		}
+		return out
+	}
+
+	// Genre matches case-insensitively and the total reflects the filter
+	found, total, err := repo.List(ctx, repository.BookFilter{Genre: "FANTASY", SortBy: repository.BookSortYear}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{"The Hobbit", "The Two Towers"}, titles(found))
+
+	found, total, err = repo.List(ctx, repository.BookFilter{YearFrom: 1950, YearTo: 1965}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	found, _, err = repo.List(ctx, repository.BookFilter{AuthorID: herbert.ID, SortBy: repository.BookSortTitle, SortDesc: true}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Dune", "100%_Pure"}, titles(found))
+
+	available := false
+	found, total, err = repo.List(ctx, repository.BookFilter{Available: &available}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "The Hobbit", found[0].Title)
+
+	// q matches the author's name as well as the title
+	_, total, err = repo.List(ctx, repository.BookFilter{Query: "tolkien"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	// LIKE wildcards in q are matched literally
+	found, total, err = repo.List(ctx, repository.BookFilter{Query: "%_"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "100%_Pure", found[0].Title)
+
+	// Paging keeps the filtered total
+	found, total, err = repo.List(ctx, repository.BookFilter{Genre: "fantasy", SortBy: repository.BookSortTitle}, 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{"The Two Towers"}, titles(found))
+}
+
+func TestBookRepository_ListByCursor(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+
+	author := &models.Author{Name: "Test Author"}
+	assert.NoError(t, db.Create(author).Error)
+	publisher := &models.Publisher{Name: "Test Publisher"}
+	assert.NoError(t, db.Create(publisher).Error)
+
+	// Duplicate years exercise the id tie-break
+	years := []int{2001, 2001, 2002, 2003, 2003}
+	for i, year := range years {
+		book := &models.Book{
+			Title:       fmt.Sprintf("Book %d", i),
+			ISBN:        fmt.Sprintf("978000000000%d", i),
+			AuthorID:    author.ID,
+			PublisherID: publisher.ID,
+			Year:        year,
+			Genre:       "Test",
+			Quantity:    1,
+		}
+		assert.NoError(t, db.Create(book).Error)
+	}
+
+	filter := repository.BookFilter{SortBy: repository.BookSortYear, SortDesc: true}
+
+	// Walk forward two at a time
+	var seen []uuid.UUID
+	var cursor *repository.BookCursor
+	pages := 0
+	for {
+		page, err := repo.ListByCursor(ctx, filter, cursor, 2, pages == 0)
+		assert.NoError(t, err)
+		if pages == 0 {
+			assert.Nil(t, page.Prev)
+			if assert.NotNil(t, page.Total) {
+				assert.Equal(t, int64(5), *page.Total)
+			}
+		} else {
+			assert.Nil(t, page.Total)
+		}
+		for _, book := range page.Books {
+			seen = append(seen, book.ID)
+		}
+		pages++
+		if page.Next == nil {
+			break
+		}
+		cursor = page.Next
+	}
+	assert.Equal(t, 3, pages)
+
+	all, _, err := repo.List(ctx, filter, 1, 10)
+	assert.NoError(t, err)
+	var expected []uuid.UUID
+	for _, book := range all {
+		expected = append(expected, book.ID)
+	}
+	assert.Equal(t, expected, seen)
+
+	// Walking back from the last page returns the middle page in order
+	last, err := repo.ListByCursor(ctx, filter, cursor, 2, false)
+	assert.NoError(t, err)
+	middle, err := repo.ListByCursor(ctx, filter, last.Prev, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, expected[2:4], []uuid.UUID{middle.Books[0].ID, middle.Books[1].ID})
+	assert.NotNil(t, middle.Next)
+	assert.NotNil(t, middle.Prev)
+
+	// Tokens survive encoding
+	decoded, err := repository.DecodeBookCursor(middle.Prev.Encode())
+	assert.NoError(t, err)
+	first, err := repo.ListByCursor(ctx, filter, decoded, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, expected[0:2], []uuid.UUID{first.Books[0].ID, first.Books[1].ID})
+	assert.Nil(t, first.Prev)
+
+	// A cursor from another sort order is rejected
+	_, err = repo.ListByCursor(ctx, repository.BookFilter{SortBy: repository.BookSortTitle}, cursor, 2, false)
+	assert.Error(t, err)
+}
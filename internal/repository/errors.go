@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/library-api/internal/apperr"
+	"gorm.io/gorm"
+)
+
+// Postgres error codes we translate
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgQueryCanceled       = "57014"
+)
+
+var (
+	// Detail of a unique violation: Key (isbn)=(978...) already exists.
+	pgDetailKey = regexp.MustCompile(`^Key \(([^)]+)\)`)
+	// Detail of a foreign key violation names the other table
+	pgDetailTable = regexp.MustCompile(`table "([^"]+)"`)
+)
+
+// translateError maps a database error onto an apperr kind. resource names
+// the row the operation was about, for not-found and duplicate messages.
+// Errors that already carry a kind, and unrecognised errors, are returned
+// unchanged.
+func translateError(err error, resource string) error {
+	if err == nil || apperr.IsTyped(err) {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperr.Wrap(apperr.ErrNotFound, err, "%s not found", resource)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == pgUniqueViolation:
+			if m := pgDetailKey.FindStringSubmatch(pgErr.Detail); m != nil {
+				return apperr.Wrap(apperr.ErrConflict, err, "a %s with this %s already exists", resource, m[1])
+			}
+			return apperr.Wrap(apperr.ErrConflict, err, "%s already exists", resource)
+		case pgErr.Code == pgForeignKeyViolation:
+			table := "another record"
+			if m := pgDetailTable.FindStringSubmatch(pgErr.Detail); m != nil {
+				table = m[1]
+			}
+			// Writing a row that points at a missing one is bad input;
+			// deleting a row others still point at is a conflict
+			if strings.HasPrefix(pgErr.Message, "insert or update") {
+				return apperr.Wrap(apperr.ErrValidation, err, "%s refers to a missing %s", resource, strings.TrimSuffix(table, "s"))
+			}
+			return apperr.Wrap(apperr.ErrConflict, err, "%s is still referenced by %s", resource, table)
+		case pgErr.Code == pgCheckViolation:
+			return apperr.Wrap(apperr.ErrConflict, err, "%s violates constraint %s", resource, pgErr.ConstraintName)
+		case pgErr.Code == pgQueryCanceled,
+			// connection exceptions, insufficient resources, operator intervention
+			strings.HasPrefix(pgErr.Code, "08"),
+			strings.HasPrefix(pgErr.Code, "53"),
+			strings.HasPrefix(pgErr.Code, "57P"):
+			return apperr.Wrap(apperr.ErrUnavailable, err, "database unavailable")
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || pgconn.Timeout(err) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) {
+		return apperr.Wrap(apperr.ErrUnavailable, err, "database unavailable")
+	}
+
+	return err
+}
+
+// deleteResult translates the outcome of deleting one row by ID. A delete
+// that matched no row is reported as not found.
+func deleteResult(result *gorm.DB, resource string) error {
+	if result.Error != nil {
+		return translateError(result.Error, resource)
+	}
+	if result.RowsAffected == 0 {
+		return apperr.NotFound("%s not found", resource)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FineRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Fine, error)
+	ListByMember(ctx context.Context, memberID uuid.UUID) ([]models.Fine, error)
+	OutstandingBalance(ctx context.Context, memberID uuid.UUID) (int64, error)
+	Pay(ctx context.Context, id uuid.UUID, amountCents int64, note string) (*models.Fine, error)
+	Waive(ctx context.Context, id uuid.UUID, amountCents int64, note string) (*models.Fine, error)
+	AccrueFines(ctx context.Context, now time.Time, dailyRateCents, capCents int64) (int, error)
+}
+
+type fineRepository struct {
+	db *gorm.DB
+}
+
+func NewFineRepository(db *gorm.DB) FineRepository {
+	return &fineRepository{db: db}
+}
+
+func (r *fineRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Fine, error) {
+	var fine models.Fine
+	err := r.db.WithContext(ctx).Preload("Loan").
+		Preload("Entries", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		First(&fine, id).Error
+	if err != nil {
+		return nil, translateError(err, "fine")
+	}
+	return &fine, nil
+}
+
+func (r *fineRepository) ListByMember(ctx context.Context, memberID uuid.UUID) ([]models.Fine, error) {
+	var fines []models.Fine
+	err := r.db.WithContext(ctx).Preload("Loan").
+		Where("member_id = ?", memberID).
+		Order("created_at DESC").
+		Find(&fines).Error
+	if err != nil {
+		return nil, translateError(err, "fine")
+	}
+	return fines, nil
+}
+
+func (r *fineRepository) OutstandingBalance(ctx context.Context, memberID uuid.UUID) (int64, error) {
+	balance, err := outstandingBalance(r.db.WithContext(ctx), memberID)
+	return balance, translateError(err, "fine")
+}
+
+// outstandingBalance sums what a member still owes; db may be a transaction
+func outstandingBalance(db *gorm.DB, memberID uuid.UUID) (int64, error) {
+	var balance int64
+	err := db.Model(&models.Fine{}).
+		Where("member_id = ?", memberID).
+		Select("COALESCE(SUM(balance_cents), 0)").
+		Scan(&balance).Error
+	return balance, err
+}
+
+func (r *fineRepository) Pay(ctx context.Context, id uuid.UUID, amountCents int64, note string) (*models.Fine, error) {
+	return r.settle(ctx, id, models.FineEntryPayment, amountCents, note)
+}
+
+// Waive forgives part of a fine; an amount of zero waives the whole balance
+func (r *fineRepository) Waive(ctx context.Context, id uuid.UUID, amountCents int64, note string) (*models.Fine, error) {
+	return r.settle(ctx, id, models.FineEntryWaiver, amountCents, note)
+}
+
+// settle reduces a fine's balance by a payment or waiver and records it in
+// the ledger.
+func (r *fineRepository) settle(ctx context.Context, id uuid.UUID, kind string, amountCents int64, note string) (*models.Fine, error) {
+	var fine models.Fine
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&fine, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if kind == models.FineEntryWaiver && amountCents == 0 {
+			amountCents = fine.BalanceCents
+		}
+		if amountCents <= 0 {
+			return apperr.Validation("amount must be positive")
+		}
+		if amountCents > fine.BalanceCents {
+			return apperr.Conflict("amount exceeds outstanding balance of %d cents", fine.BalanceCents)
+		}
+
+		if kind == models.FineEntryPayment {
+			fine.PaidCents += amountCents
+		} else {
+			fine.WaivedCents += amountCents
+		}
+		fine.BalanceCents -= amountCents
+
+		if err := tx.Save(&fine).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.FineEntry{
+			FineID:      fine.ID,
+			Kind:        kind,
+			AmountCents: amountCents,
+			Note:        note,
+		}).Error
+	})
+
+	if err != nil {
+		return nil, translateError(err, "fine")
+	}
+
+	return r.GetByID(ctx, fine.ID)
+}
+
+// AccrueFines marks loans that went past their due date as overdue, opens a
+// fine for each, and brings every accruing fine up to dailyRateCents per full
+// day late, capped at capCents per loan. It returns the number of fines that
+// grew.
+func (r *fineRepository) AccrueFines(ctx context.Context, now time.Time, dailyRateCents, capCents int64) (int, error) {
+	db := r.db.WithContext(ctx)
+
+	err := db.Model(&models.Loan{}).
+		Where("overdue = ? AND due_at < COALESCE(returned_at, ?)", false, now).
+		Update("overdue", true).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var unfined []models.Loan
+	err = db.Joins("LEFT JOIN fines ON fines.loan_id = loans.id").
+		Where("loans.overdue = ? AND fines.id IS NULL", true).
+		Find(&unfined).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, loan := range unfined {
+		fine := models.Fine{LoanID: loan.ID, MemberID: loan.MemberID, Accruing: true}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&fine).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	var accruing []models.Fine
+	if err := db.Where("accruing = ?", true).Find(&accruing).Error; err != nil {
+		return 0, err
+	}
+
+	grown := 0
+	for _, f := range accruing {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var fine models.Fine
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Preload("Loan").
+				First(&fine, "id = ?", f.ID).Error; err != nil {
+				return err
+			}
+
+			end := now
+			if fine.Loan.ReturnedAt != nil {
+				end = *fine.Loan.ReturnedAt
+			}
+
+			daysLate := int64(end.Sub(fine.Loan.DueAt) / (24 * time.Hour))
+			if daysLate < 0 {
+				daysLate = 0
+			}
+			target := daysLate * dailyRateCents
+			if target > capCents {
+				target = capCents
+			}
+
+			// Stop accruing once the loan is back or the cap is hit
+			fine.Accruing = fine.Loan.ReturnedAt == nil && target < capCents
+
+			if diff := target - fine.AmountCents; diff > 0 {
+				fine.AmountCents += diff
+				fine.BalanceCents += diff
+				if err := tx.Create(&models.FineEntry{
+					FineID:      fine.ID,
+					Kind:        models.FineEntryAccrual,
+					AmountCents: diff,
+					Note:        fmt.Sprintf("%d day(s) overdue", daysLate),
+				}).Error; err != nil {
+					return err
+				}
+				grown++
+			}
+
+			fine.Loan = nil
+			return tx.Save(&fine).Error
+		})
+		if err != nil {
+			return grown, err
+		}
+	}
+
+	return grown, nil
+}
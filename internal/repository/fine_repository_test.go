@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFineRepository_AccrueAndSettle(t *testing.T) {
+	db := setupTestDB(t)
+	bookRepo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+	repo := repository.NewFineRepository(db)
+
+	book, members := seedSingleCopyBook(t, db, "alice")
+	alice := members[0]
+
+	// Due three and a half days ago
+	now := time.Now()
+	_, err := bookRepo.IssueBook(ctx, book.ID, alice.ID, now.Add(-84*time.Hour), 0)
+	assert.NoError(t, err)
+
+	grown, err := repo.AccrueFines(ctx, now, 25, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, grown)
+
+	var loan models.Loan
+	assert.NoError(t, db.First(&loan, "member_id = ?", alice.ID).Error)
+	assert.True(t, loan.Overdue)
+
+	fines, err := repo.ListByMember(ctx, alice.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fines))
+	assert.Equal(t, int64(75), fines[0].AmountCents)
+	assert.True(t, fines[0].Accruing)
+
+	// Running again on the same day charges nothing more
+	grown, err = repo.AccrueFines(ctx, now, 25, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, grown)
+
+	// Two months later the fine stops at the cap
+	_, err = repo.AccrueFines(ctx, now.Add(60*24*time.Hour), 25, 1000)
+	assert.NoError(t, err)
+
+	fine, err := repo.GetByID(ctx, fines[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), fine.AmountCents)
+	assert.Equal(t, int64(1000), fine.BalanceCents)
+	assert.False(t, fine.Accruing)
+	assert.Equal(t, 2, len(fine.Entries))
+
+	balance, err := repo.OutstandingBalance(ctx, alice.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), balance)
+
+	// Members owing more than the limit cannot borrow
+	_, err = bookRepo.IssueBook(ctx, book.ID, alice.ID, now.Add(time.Hour), 500)
+	assert.ErrorIs(t, err, repository.ErrFinesOwed)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+
+	// Pay part, refuse overpayment, waive the rest
+	fine, err = repo.Pay(ctx, fine.ID, 400, "cash")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(600), fine.BalanceCents)
+
+	_, err = repo.Pay(ctx, fine.ID, 601, "too much")
+	assert.Error(t, err)
+
+	fine, err = repo.Waive(ctx, fine.ID, 0, "first offence")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), fine.BalanceCents)
+	assert.Equal(t, int64(400), fine.PaidCents)
+	assert.Equal(t, int64(600), fine.WaivedCents)
+	assert.Equal(t, 4, len(fine.Entries))
+
+	balance, err = repo.OutstandingBalance(ctx, alice.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), balance)
+}
+
+func TestFineRepository_ReturnedLateStopsAccruing(t *testing.T) {
+	db := setupTestDB(t)
+	bookRepo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+	repo := repository.NewFineRepository(db)
+
+	book, members := seedSingleCopyBook(t, db, "alice")
+	alice := members[0]
+
+	// Issued two days overdue, then returned before the job ever ran
+	_, err := bookRepo.IssueBook(ctx, book.ID, alice.ID, time.Now().Add(-48*time.Hour-time.Minute), 0)
+	assert.NoError(t, err)
+	_, err = bookRepo.ReturnBook(ctx, book.ID, alice.ID, time.Hour)
+	assert.NoError(t, err)
+
+	_, err = repo.AccrueFines(ctx, time.Now().Add(10*24*time.Hour), 25, 1000)
+	assert.NoError(t, err)
+
+	fines, err := repo.ListByMember(ctx, alice.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fines))
+	assert.Equal(t, int64(50), fines[0].AmountCents)
+	assert.False(t, fines[0].Accruing)
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HoldRepository interface {
+	PlaceHold(ctx context.Context, bookID, memberID uuid.UUID) (*models.Hold, error)
+	CancelHold(ctx context.Context, id uuid.UUID, pickupWindow time.Duration) (*models.Hold, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Hold, error)
+	ListByBook(ctx context.Context, bookID uuid.UUID) ([]models.Hold, error)
+	ListByMember(ctx context.Context, memberID uuid.UUID) ([]models.Hold, error)
+	ProcessHolds(ctx context.Context, pickupWindow time.Duration) (int, error)
+}
+
+type holdRepository struct {
+	db *gorm.DB
+}
+
+func NewHoldRepository(db *gorm.DB) HoldRepository {
+	return &holdRepository{db: db}
+}
+
+func (r *holdRepository) PlaceHold(ctx context.Context, bookID, memberID uuid.UUID) (*models.Hold, error) {
+	var hold models.Hold
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the book so availability cannot change while we queue
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&book, "id = ?", bookID).Error; err != nil {
+			return translateError(err, "book")
+		}
+
+		var member models.Member
+		if err := tx.First(&member, "id = ?", memberID).Error; err != nil {
+			return translateError(err, "member")
+		}
+
+		var existing int64
+		if err := tx.Model(&models.Hold{}).
+			Where("book_id = ? AND member_id = ? AND status IN ?", bookID, memberID, activeHoldStatuses).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return apperr.Conflict("member already has a hold on this book")
+		}
+
+		free, err := freeCopies(tx, &book)
+		if err != nil {
+			return err
+		}
+		if free > 0 {
+			return apperr.Conflict("copies are available, issue the book instead")
+		}
+
+		hold = models.Hold{
+			BookID:   bookID,
+			MemberID: memberID,
+			Status:   models.HoldStatusWaiting,
+		}
+		return tx.Create(&hold).Error
+	})
+
+	if err != nil {
+		return nil, translateError(err, "hold")
+	}
+
+	return &hold, nil
+}
+
+func (r *holdRepository) CancelHold(ctx context.Context, id uuid.UUID, pickupWindow time.Duration) (*models.Hold, error) {
+	var hold models.Hold
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&hold, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&book, "id = ?", hold.BookID).Error; err != nil {
+			return err
+		}
+
+		// Re-read under the book lock in case the hold changed meanwhile
+		if err := tx.First(&hold, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if hold.Status != models.HoldStatusWaiting && hold.Status != models.HoldStatusReady {
+			return apperr.Conflict("hold is no longer active")
+		}
+
+		wasReady := hold.Status == models.HoldStatusReady
+		hold.Status = models.HoldStatusCancelled
+		if err := tx.Save(&hold).Error; err != nil {
+			return err
+		}
+
+		// A cancelled ready hold releases its copy to the next in line
+		if wasReady {
+			return promoteHolds(tx, &book, pickupWindow)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, translateError(err, "hold")
+	}
+
+	return &hold, nil
+}
+
+func (r *holdRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Hold, error) {
+	var hold models.Hold
+	err := r.db.WithContext(ctx).Preload("Book").Preload("Member").First(&hold, id).Error
+	if err != nil {
+		return nil, translateError(err, "hold")
+	}
+	return &hold, nil
+}
+
+// ListByBook returns the active queue for a book in the order it is served
+func (r *holdRepository) ListByBook(ctx context.Context, bookID uuid.UUID) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := r.db.WithContext(ctx).Preload("Member").
+		Where("book_id = ? AND status IN ?", bookID, activeHoldStatuses).
+		Order("created_at").
+		Find(&holds).Error
+	if err != nil {
+		return nil, translateError(err, "hold")
+	}
+	return holds, nil
+}
+
+func (r *holdRepository) ListByMember(ctx context.Context, memberID uuid.UUID) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := r.db.WithContext(ctx).Preload("Book").
+		Where("member_id = ?", memberID).
+		Order("created_at DESC").
+		Find(&holds).Error
+	if err != nil {
+		return nil, translateError(err, "hold")
+	}
+	return holds, nil
+}
+
+// ProcessHolds expires ready holds that were not picked up in time and hands
+// any free copies to the next waiting members. It returns the number of
+// holds that expired.
+func (r *holdRepository) ProcessHolds(ctx context.Context, pickupWindow time.Duration) (int, error) {
+	db := r.db.WithContext(ctx)
+
+	var bookIDs []uuid.UUID
+	err := db.Model(&models.Hold{}).
+		Where("status = ? OR (status = ? AND expires_at <= ?)",
+			models.HoldStatusWaiting, models.HoldStatusReady, time.Now()).
+		Distinct().
+		Pluck("book_id", &bookIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, bookID := range bookIDs {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var book models.Book
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&book, "id = ?", bookID).Error; err != nil {
+				return err
+			}
+
+			result := tx.Model(&models.Hold{}).
+				Where("book_id = ? AND status = ? AND expires_at <= ?",
+					bookID, models.HoldStatusReady, time.Now()).
+				Update("status", models.HoldStatusExpired)
+			if result.Error != nil {
+				return result.Error
+			}
+			expired += int(result.RowsAffected)
+
+			return promoteHolds(tx, &book, pickupWindow)
+		})
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return expired, err
+		}
+	}
+
+	return expired, nil
+}
+
+var activeHoldStatuses = []string{models.HoldStatusWaiting, models.HoldStatusReady}
+
+// freeCopies returns the copies of a book that are neither on loan nor set
+// aside for a ready hold. Callers must hold the book row lock.
+func freeCopies(tx *gorm.DB, book *models.Book) (int64, error) {
+	issued, err := countIssuedCopies(tx, book)
+	if err != nil {
+		return 0, err
+	}
+
+	reserved, err := countReadyHolds(tx, book.ID, uuid.Nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(book.Quantity) - issued - reserved, nil
+}
+
+// countReadyHolds returns the unexpired ready holds on a book, ignoring any
+// held by exceptMember.
+func countReadyHolds(tx *gorm.DB, bookID, exceptMember uuid.UUID) (int64, error) {
+	var count int64
+	err := tx.Model(&models.Hold{}).
+		Where("book_id = ? AND member_id <> ? AND status = ? AND expires_at > ?",
+			bookID, exceptMember, models.HoldStatusReady, time.Now()).
+		Count(&count).Error
+	return count, err
+}
+
+// promoteHolds moves waiting holds to ready, oldest first, for as long as the
+// book has free copies. Callers must hold the book row lock.
+func promoteHolds(tx *gorm.DB, book *models.Book, pickupWindow time.Duration) error {
+	free, err := freeCopies(tx, book)
+	if err != nil {
+		return err
+	}
+
+	for ; free > 0; free-- {
+		var next models.Hold
+		err := tx.Where("book_id = ? AND status = ?", book.ID, models.HoldStatusWaiting).
+			Order("created_at").
+			First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		expiresAt := now.Add(pickupWindow)
+		next.Status = models.HoldStatusReady
+		next.ReadyAt = &now
+		next.ExpiresAt = &expiresAt
+		if err := tx.Save(&next).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestHoldRepository_Queue(t *testing.T) {
+	db := setupTestDB(t)
+	bookRepo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+	repo := repository.NewHoldRepository(db)
+
+	book, members := seedSingleCopyBook(t, db, "alice", "bob", "carol", "dave")
+	alice, bob, carol, dave := members[0], members[1], members[2], members[3]
+	dueAt := time.Now().Add(14 * 24 * time.Hour)
+	window := time.Hour
+
+	// No hold while a copy is on the shelf
+	_, err := repo.PlaceHold(ctx, book.ID, bob.ID)
+	assert.Error(t, err)
+
+	_, err = bookRepo.IssueBook(ctx, book.ID, alice.ID, dueAt, 0)
+	assert.NoError(t, err)
+
+	bobHold, err := repo.PlaceHold(ctx, book.ID, bob.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldStatusWaiting, bobHold.Status)
+
+	_, err = repo.PlaceHold(ctx, book.ID, bob.ID)
+	assert.Error(t, err, "duplicate hold must be rejected")
+
+	carolHold, err := repo.PlaceHold(ctx, book.ID, carol.ID)
+	assert.NoError(t, err)
+
+	queue, err := repo.ListByBook(ctx, book.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(queue))
+	assert.Equal(t, bobHold.ID, queue[0].ID)
+
+	// Returning the copy sets it aside for the first hold in the queue
+	_, err = bookRepo.ReturnBook(ctx, book.ID, alice.ID, window)
+	assert.NoError(t, err)
+
+	bobHold, err = repo.GetByID(ctx, bobHold.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldStatusReady, bobHold.Status)
+	assert.NotNil(t, bobHold.ExpiresAt)
+
+	// The reserved copy cannot go to someone else
+	_, err = bookRepo.IssueBook(ctx, book.ID, dave.ID, dueAt, 0)
+	assert.Error(t, err)
+
+	// Bob picks it up, fulfilling his hold
+	_, err = bookRepo.IssueBook(ctx, book.ID, bob.ID, dueAt, 0)
+	assert.NoError(t, err)
+
+	bobHold, err = repo.GetByID(ctx, bobHold.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldStatusFulfilled, bobHold.Status)
+
+	// Carol's copy is set aside on return but she never collects it
+	_, err = bookRepo.ReturnBook(ctx, book.ID, bob.ID, -time.Minute)
+	assert.NoError(t, err)
+
+	carolHold, err = repo.GetByID(ctx, carolHold.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldStatusReady, carolHold.Status)
+
+	expired, err := repo.ProcessHolds(ctx, window)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+
+	carolHold, err = repo.GetByID(ctx, carolHold.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldStatusExpired, carolHold.Status)
+
+	// With the queue empty the copy is free again
+	_, err = bookRepo.IssueBook(ctx, book.ID, dave.ID, dueAt, 0)
+	assert.NoError(t, err)
+}
+
+func TestHoldRepository_CancelReadyHoldPromotesNext(t *testing.T) {
+	db := setupTestDB(t)
+	bookRepo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+	repo := repository.NewHoldRepository(db)
+
+	book, members := seedSingleCopyBook(t, db, "alice", "bob", "carol")
+	alice, bob, carol := members[0], members[1], members[2]
+	window := time.Hour
+
+	_, err := bookRepo.IssueBook(ctx, book.ID, alice.ID, time.Now().Add(time.Hour), 0)
+	assert.NoError(t, err)
+	bobHold, err := repo.PlaceHold(ctx, book.ID, bob.ID)
+	assert.NoError(t, err)
+	carolHold, err := repo.PlaceHold(ctx, book.ID, carol.ID)
+	assert.NoError(t, err)
+
+	_, err = bookRepo.ReturnBook(ctx, book.ID, alice.ID, window)
+	assert.NoError(t, err)
+
+	cancelled, err := repo.CancelHold(ctx, bobHold.ID, window)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldStatusCancelled, cancelled.Status)
+
+	carolHold, err = repo.GetByID(ctx, carolHold.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldStatusReady, carolHold.Status)
+
+	_, err = repo.CancelHold(ctx, bobHold.ID, window)
+	assert.Error(t, err, "cancelling twice must fail")
+
+	holds, err := repo.ListByMember(ctx, bob.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(holds))
+}
+
+// seedSingleCopyBook creates a book with one copy and a member per name
+func seedSingleCopyBook(t *testing.T, db *gorm.DB, names ...string) (*models.Book, []*models.Member) {
+	author := &models.Author{Name: "Test Author"}
+	assert.NoError(t, db.Create(author).Error)
+	publisher := &models.Publisher{Name: "Test Publisher"}
+	assert.NoError(t, db.Create(publisher).Error)
+
+	book := &models.Book{
+		Title:       "Popular Book",
+		ISBN:        "1234567890123",
+		AuthorID:    author.ID,
+		PublisherID: publisher.ID,
+		Year:        2025,
+		Genre:       "Test",
+		Quantity:    1,
+	}
+	assert.NoError(t, db.Create(book).Error)
+
+	members := make([]*models.Member, 0, len(names))
+	for _, name := range names {
+		member := &models.Member{Name: name, Email: name + "@example.com"}
+		assert.NoError(t, db.Create(member).Error)
+		members = append(members, member)
+	}
+
+	return book, members
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// Loan status filters accepted by LoanRepository.List
+const (
+	LoanStatusActive   = "active"
+	LoanStatusReturned = "returned"
+	LoanStatusOverdue  = "overdue"
+)
+
+// LoanFilter narrows a loan listing. Zero values match everything.
+type LoanFilter struct {
+	BookID   uuid.UUID
+	MemberID uuid.UUID
+	Status   string
+}
+
+type LoanRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Loan, error)
+	List(ctx context.Context, filter LoanFilter, page, limit int) ([]models.Loan, int64, error)
+}
+
+type loanRepository struct {
+	db *gorm.DB
+}
+
+func NewLoanRepository(db *gorm.DB) LoanRepository {
+	return &loanRepository{db: db}
+}
+
+func (r *loanRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Loan, error) {
+	var loan models.Loan
+	err := r.db.WithContext(ctx).Preload("Book").Preload("Member").First(&loan, id).Error
+	if err != nil {
+		return nil, translateError(err, "loan")
+	}
+	return &loan, nil
+}
+
+func (r *loanRepository) List(ctx context.Context, filter LoanFilter, page, limit int) ([]models.Loan, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var loans []models.Loan
+	var total int64
+
+	offset := (page - 1) * limit
+
+	byFilter := func(db *gorm.DB) *gorm.DB {
+		if filter.BookID != uuid.Nil {
+			db = db.Where("book_id = ?", filter.BookID)
+		}
+		if filter.MemberID != uuid.Nil {
+			db = db.Where("member_id = ?", filter.MemberID)
+		}
+		switch filter.Status {
+		case LoanStatusActive:
+			db = db.Where("returned_at IS NULL")
+		case LoanStatusReturned:
+			db = db.Where("returned_at IS NOT NULL")
+		case LoanStatusOverdue:
+			db = db.Where("returned_at IS NULL AND overdue = ?", true)
+		}
+		return db
+	}
+
+	err := db.Model(&models.Loan{}).Scopes(byFilter).Count(&total).Error
+	if err != nil {
+		return nil, 0, translateError(err, "loan")
+	}
+
+	err = db.Scopes(byFilter).
+		Preload("Book").Preload("Member").
+		Order("issued_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&loans).Error
+	if err != nil {
+		return nil, 0, translateError(err, "loan")
+	}
+
+	return loans, total, nil
+}
@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoanRepository_List(t *testing.T) {
+	db := setupTestDB(t)
+	bookRepo := repository.NewBookRepository(db, 0)
+	ctx := context.Background()
+	repo := repository.NewLoanRepository(db)
+
+	author := &models.Author{Name: "Test Author"}
+	assert.NoError(t, db.Create(author).Error)
+	publisher := &models.Publisher{Name: "Test Publisher"}
+	assert.NoError(t, db.Create(publisher).Error)
+	alice := &models.Member{Name: "Alice", Email: "alice@example.com"}
+	assert.NoError(t, db.Create(alice).Error)
+	bob := &models.Member{Name: "Bob", Email: "bob@example.com"}
+	assert.NoError(t, db.Create(bob).Error)
+
+	book := &models.Book{
+		Title:       "Test Book",
+		ISBN:        "1234567890123",
+		AuthorID:    author.ID,
+		PublisherID: publisher.ID,
+		Year:        2025,
+		Genre:       "Test",
+		Quantity:    3,
+	}
+	assert.NoError(t, bookRepo.Create(ctx, book))
+
+	dueAt := time.Now().Add(14 * 24 * time.Hour)
+	_, err := bookRepo.IssueBook(ctx, book.ID, alice.ID, dueAt, 0)
+	assert.NoError(t, err)
+	_, err = bookRepo.IssueBook(ctx, book.ID, bob.ID, dueAt, 0)
+	assert.NoError(t, err)
+	_, err = bookRepo.ReturnBook(ctx, book.ID, alice.ID, time.Hour)
+	assert.NoError(t, err)
+
+	// Per book
+	loans, total, err := repo.List(ctx, repository.LoanFilter{BookID: book.ID}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, 2, len(loans))
+
+	active, total, err := repo.List(ctx, repository.LoanFilter{BookID: book.ID, Status: repository.LoanStatusActive}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, bob.ID, active[0].MemberID)
+	assert.WithinDuration(t, dueAt, active[0].DueAt, time.Second)
+
+	// Per member
+	history, total, err := repo.List(ctx, repository.LoanFilter{MemberID: alice.ID, Status: repository.LoanStatusReturned}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.NotNil(t, history[0].ReturnedAt)
+	assert.Equal(t, book.Title, history[0].Book.Title)
+
+	// GetByID
+	found, err := repo.GetByID(ctx, history[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, alice.Email, found.Member.Email)
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type MemberRepository interface {
+	Create(ctx context.Context, member *models.Member) error
+	Update(ctx context.Context, member *models.Member) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Member, error)
+	GetBySubject(ctx context.Context, subject string) (*models.Member, error)
+	List(ctx context.Context, page, limit int) ([]models.Member, int64, error)
+}
+
+type memberRepository struct {
+	db *gorm.DB
+}
+
+func NewMemberRepository(db *gorm.DB) MemberRepository {
+	return &memberRepository{db: db}
+}
+
+func (r *memberRepository) Create(ctx context.Context, member *models.Member) error {
+	return translateError(r.db.WithContext(ctx).Create(member).Error, "member")
+}
+
+func (r *memberRepository) Update(ctx context.Context, member *models.Member) error {
+	// Only update scalar fields to avoid touching the Loans association
+	err := r.db.WithContext(ctx).Model(&models.Member{}).
+		Where("id = ?", member.ID).
+		Updates(map[string]interface{}{
+			"name":       member.Name,
+			"email":      member.Email,
+			"phone":      member.Phone,
+			"subject":    member.Subject,
+			"updated_at": member.UpdatedAt,
+		}).Error
+	return translateError(err, "member")
+}
+
+func (r *memberRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return deleteResult(r.db.WithContext(ctx).Delete(&models.Member{}, id), "member")
+}
+
+func (r *memberRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Member, error) {
+	var member models.Member
+	err := r.db.WithContext(ctx).First(&member, id).Error
+	if err != nil {
+		return nil, translateError(err, "member")
+	}
+	return &member, nil
+}
+
+func (r *memberRepository) GetBySubject(ctx context.Context, subject string) (*models.Member, error) {
+	var member models.Member
+	err := r.db.WithContext(ctx).Where("subject = ?", subject).First(&member).Error
+	if err != nil {
+		return nil, translateError(err, "member")
+	}
+	return &member, nil
+}
+
+func (r *memberRepository) List(ctx context.Context, page, limit int) ([]models.Member, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var members []models.Member
+	var total int64
+
+	offset := (page - 1) * limit
+
+	err := db.Model(&models.Member{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, translateError(err, "member")
+	}
+
+	err = db.Offset(offset).
+		Limit(limit).
+		Find(&members).Error
+	if err != nil {
+		return nil, 0, translateError(err, "member")
+	}
+
+	return members, total, nil
+}
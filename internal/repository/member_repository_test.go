@@ -0,0 +1,89 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemberRepository_CRUD(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	repo := repository.NewMemberRepository(db)
+
+	// Test Create
+	member := &models.Member{
+		Name:  "Test Member",
+		Email: "member@example.com",
+	}
+	err := repo.Create(ctx, member)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, member.ID)
+
+	// Emails are unique
+	err = repo.Create(ctx, &models.Member{Name: "Duplicate", Email: "member@example.com"})
+	assert.Error(t, err)
+
+	// Test GetByID
+	found, err := repo.GetByID(ctx, member.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, member.Email, found.Email)
+
+	// Test Update
+	member.Phone = "555-0100"
+	err = repo.Update(ctx, member)
+	assert.NoError(t, err)
+
+	updated, err := repo.GetByID(ctx, member.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, member.Phone, updated.Phone)
+
+	// Test List
+	members, total, err := repo.List(ctx, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, 1, len(members))
+
+	// Test Delete
+	err = repo.Delete(ctx, member.ID)
+	assert.NoError(t, err)
+
+	_, err = repo.GetByID(ctx, member.ID)
+	assert.Error(t, err)
+
+	// Deleting a missing member is reported
+	err = repo.Delete(ctx, member.ID)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func TestMemberRepository_GetBySubject(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	repo := repository.NewMemberRepository(db)
+
+	subject := "user-123"
+	linked := &models.Member{Name: "Linked", Email: "linked@example.com", Subject: &subject}
+	assert.NoError(t, repo.Create(ctx, linked))
+	// Any number of members can be unlinked
+	assert.NoError(t, repo.Create(ctx, &models.Member{Name: "Walk-in", Email: "walkin@example.com"}))
+	assert.NoError(t, repo.Create(ctx, &models.Member{Name: "Walk-in 2", Email: "walkin2@example.com"}))
+
+	found, err := repo.GetBySubject(ctx, subject)
+	assert.NoError(t, err)
+	assert.Equal(t, linked.ID, found.ID)
+
+	// A subject links to one member only
+	err = repo.Create(ctx, &models.Member{Name: "Other", Email: "other@example.com", Subject: &subject})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+
+	// Unlinking frees the subject
+	linked.Subject = nil
+	assert.NoError(t, repo.Update(ctx, linked))
+	_, err = repo.GetBySubject(ctx, subject)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
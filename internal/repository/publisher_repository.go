@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// publisherTreeSQL selects the IDs of a publisher and all of its imprints,
+// however deeply nested.
+const publisherTreeSQL = `
+	WITH RECURSIVE publisher_tree AS (
+		SELECT id FROM publishers WHERE id = ?
+		UNION
+		SELECT p.id FROM publishers p
+		JOIN publisher_tree t ON p.parent_id = t.id
+	)
+	SELECT id FROM publisher_tree`
+
+type PublisherRepository interface {
+	Create(ctx context.Context, publisher *models.Publisher) error
+	Update(ctx context.Context, publisher *models.Publisher) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Publisher, error)
+	List(ctx context.Context, page, limit int) ([]models.Publisher, int64, error)
+	ListImprints(ctx context.Context, id uuid.UUID) ([]models.Publisher, error)
+	DescendantIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	ListBooks(ctx context.Context, id uuid.UUID, includeImprints bool, page, limit int) ([]models.Book, int64, error)
+}
+
+type publisherRepository struct {
+	db *gorm.DB
+}
+
+func NewPublisherRepository(db *gorm.DB) PublisherRepository {
+	return &publisherRepository{db: db}
+}
+
+func (r *publisherRepository) Create(ctx context.Context, publisher *models.Publisher) error {
+	return translateError(r.db.WithContext(ctx).Create(publisher).Error, "publisher")
+}
+
+func (r *publisherRepository) Update(ctx context.Context, publisher *models.Publisher) error {
+	// Only update scalar fields to avoid touching the Imprints/Books associations
+	err := r.db.WithContext(ctx).Model(&models.Publisher{}).
+		Where("id = ?", publisher.ID).
+		Updates(map[string]interface{}{
+			"name":       publisher.Name,
+			"location":   publisher.Location,
+			"parent_id":  publisher.ParentID,
+			"updated_at": publisher.UpdatedAt,
+		}).Error
+	return translateError(err, "publisher")
+}
+
+func (r *publisherRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return deleteResult(r.db.WithContext(ctx).Delete(&models.Publisher{}, id), "publisher")
+}
+
+func (r *publisherRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Publisher, error) {
+	var publisher models.Publisher
+	err := r.db.WithContext(ctx).Preload("Parent").Preload("Imprints").First(&publisher, id).Error
+	if err != nil {
+		return nil, translateError(err, "publisher")
+	}
+	return &publisher, nil
+}
+
+func (r *publisherRepository) List(ctx context.Context, page, limit int) ([]models.Publisher, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var publishers []models.Publisher
+	var total int64
+
+	offset := (page - 1) * limit
+
+	err := db.Model(&models.Publisher{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, translateError(err, "publisher")
+	}
+
+	err = db.Offset(offset).
+		Limit(limit).
+		Find(&publishers).Error
+	if err != nil {
+		return nil, 0, translateError(err, "publisher")
+	}
+
+	return publishers, total, nil
+}
+
+func (r *publisherRepository) ListImprints(ctx context.Context, id uuid.UUID) ([]models.Publisher, error) {
+	var imprints []models.Publisher
+	err := r.db.WithContext(ctx).Where("parent_id = ?", id).Find(&imprints).Error
+	if err != nil {
+		return nil, translateError(err, "publisher")
+	}
+	return imprints, nil
+}
+
+// DescendantIDs returns the IDs of every imprint below the given publisher,
+// excluding the publisher itself.
+func (r *publisherRepository) DescendantIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(publisherTreeSQL, id).Scan(&ids).Error
+	if err != nil {
+		return nil, translateError(err, "publisher")
+	}
+
+	descendants := make([]uuid.UUID, 0, len(ids))
+	for _, d := range ids {
+		if d != id {
+			descendants = append(descendants, d)
+		}
+	}
+	return descendants, nil
+}
+
+func (r *publisherRepository) ListBooks(ctx context.Context, id uuid.UUID, includeImprints bool, page, limit int) ([]models.Book, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var books []models.Book
+	var total int64
+
+	offset := (page - 1) * limit
+
+	byPublisher := func(db *gorm.DB) *gorm.DB {
+		if includeImprints {
+			return db.Where("publisher_id IN ("+publisherTreeSQL+")", id)
+		}
+		return db.Where("publisher_id = ?", id)
+	}
+
+	err := db.Model(&models.Book{}).Scopes(byPublisher).Count(&total).Error
+	if err != nil {
+		return nil, 0, translateError(err, "publisher")
+	}
+
+	err = db.Scopes(byPublisher).
+		Preload("Author").Preload("Publisher").
+		Offset(offset).
+		Limit(limit).
+		Find(&books).Error
+	if err != nil {
+		return nil, 0, translateError(err, "publisher")
+	}
+
+	return books, total, nil
+}
@@ -0,0 +1,119 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublisherRepository_CRUD(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	repo := repository.NewPublisherRepository(db)
+
+	// Test Create
+	publisher := &models.Publisher{
+		Name:     "Test Publisher",
+		Location: "Test Location",
+	}
+	err := repo.Create(ctx, publisher)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, publisher.ID)
+
+	// Test GetByID
+	found, err := repo.GetByID(ctx, publisher.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, publisher.Name, found.Name)
+
+	// Test Update
+	publisher.Location = "Updated Location"
+	err = repo.Update(ctx, publisher)
+	assert.NoError(t, err)
+
+	updated, err := repo.GetByID(ctx, publisher.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, publisher.Location, updated.Location)
+
+	// Test List
+	publishers, total, err := repo.List(ctx, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, 1, len(publishers))
+
+	// Test Delete
+	err = repo.Delete(ctx, publisher.ID)
+	assert.NoError(t, err)
+
+	_, err = repo.GetByID(ctx, publisher.ID)
+	assert.Error(t, err)
+
+	// Deleting a missing publisher is reported
+	err = repo.Delete(ctx, publisher.ID)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func TestPublisherRepository_Imprints(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	repo := repository.NewPublisherRepository(db)
+
+	// Group -> Imprint -> Sub-imprint
+	group := &models.Publisher{Name: "Group"}
+	assert.NoError(t, repo.Create(ctx, group))
+	imprint := &models.Publisher{Name: "Imprint", ParentID: &group.ID}
+	assert.NoError(t, repo.Create(ctx, imprint))
+	subImprint := &models.Publisher{Name: "Sub-imprint", ParentID: &imprint.ID}
+	assert.NoError(t, repo.Create(ctx, subImprint))
+
+	author := &models.Author{Name: "Test Author"}
+	assert.NoError(t, db.Create(author).Error)
+
+	for i, p := range []*models.Publisher{group, imprint, subImprint} {
+		book := &models.Book{
+			Title:       "Book " + p.Name,
+			ISBN:        []string{"1234567890123", "1234567890124", "1234567890125"}[i],
+			AuthorID:    author.ID,
+			PublisherID: p.ID,
+			Year:        2025,
+			Genre:       "Test",
+			Quantity:    1,
+		}
+		assert.NoError(t, db.Create(book).Error)
+	}
+
+	// GetByID preloads direct imprints
+	found, err := repo.GetByID(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(found.Imprints))
+
+	imprints, err := repo.ListImprints(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(imprints))
+	assert.Equal(t, imprint.ID, imprints[0].ID)
+
+	descendants, err := repo.DescendantIDs(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{imprint.ID, subImprint.ID}, descendants)
+
+	_, total, err := repo.ListBooks(ctx, group.ID, false, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	books, total, err := repo.ListBooks(ctx, group.ID, true, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, 3, len(books))
+
+	// Deleting the middle imprint detaches its children rather than deleting them
+	err = repo.Delete(ctx, imprint.ID)
+	assert.NoError(t, err)
+
+	orphan, err := repo.GetByID(ctx, subImprint.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, orphan.ParentID)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// BookSearchResult is a book matched by a full-text search
+type BookSearchResult struct {
+	Book models.Book `json:"book"`
+	Rank float64     `json:"rank"`
+	// Highlight is a snippet of the matched text with terms wrapped in <mark>
+	Highlight string `json:"highlight"`
+}
+
+type SearchRepository interface {
+	SearchBooks(ctx context.Context, query string, page, limit int) ([]BookSearchResult, int64, error)
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// Headlines are only computed for the requested page, as ts_headline re-parses
+// the source text and is far more expensive than ranking.
+const bookSearchSQL = `
+SELECT ranked.id, ranked.rank,
+	ts_headline('english', concat_ws(' | ', b.title, a.name, p.name, b.genre), ranked.tsq,
+		'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20') AS highlight
+FROM (
+	SELECT books.id, ts_rank_cd(books.search_vector, tsq) AS rank, tsq
+	FROM books, websearch_to_tsquery('english', ?) tsq
+	WHERE books.search_vector @@ tsq
+	ORDER BY rank DESC, books.id
+	LIMIT ? OFFSET ?
+) ranked
+JOIN books b ON b.id = ranked.id
+JOIN authors a ON a.id = b.author_id
+JOIN publishers p ON p.id = b.publisher_id
+ORDER BY ranked.rank DESC, ranked.id`
+
+func (r *searchRepository) SearchBooks(ctx context.Context, query string, page, limit int) ([]BookSearchResult, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var total int64
+	err := db.Model(&models.Book{}).
+		Where("search_vector @@ websearch_to_tsquery('english', ?)", query).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, translateError(err, "book")
+	}
+
+	var hits []struct {
+		ID        uuid.UUID
+		Rank      float64
+		Highlight string
+	}
+	offset := (page - 1) * limit
+	if err := db.Raw(bookSearchSQL, query, limit, offset).Scan(&hits).Error; err != nil {
+		return nil, 0, translateError(err, "book")
+	}
+	if len(hits) == 0 {
+		return []BookSearchResult{}, total, nil
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+
+	var books []models.Book
+	if err := db.Preload("Author").Preload("Publisher").Where("id IN ?", ids).Find(&books).Error; err != nil {
+		return nil, 0, translateError(err, "book")
+	}
+	byID := make(map[uuid.UUID]models.Book, len(books))
+	for _, book := range books {
+		byID[book.ID] = book
+	}
+
+	// Keep the rank order; skip any book deleted between the two queries
+	results := make([]BookSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		book, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, BookSearchResult{Book: book, Rank: hit.Rank, Highlight: hit.Highlight})
+	}
+
+	return results, total, nil
+}
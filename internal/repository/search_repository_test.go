@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchRepository_SearchBooks(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	repo := repository.NewSearchRepository(db)
+
+	tolkien := &models.Author{Name: "J.R.R. Tolkien"}
+	assert.NoError(t, db.Create(tolkien).Error)
+	herbert := &models.Author{Name: "Frank Herbert"}
+	assert.NoError(t, db.Create(herbert).Error)
+
+	allen := &models.Publisher{Name: "Allen & Unwin"}
+	assert.NoError(t, db.Create(allen).Error)
+	chilton := &models.Publisher{Name: "Chilton Books"}
+	assert.NoError(t, db.Create(chilton).Error)
+
+	hobbit := &models.Book{Title: "The Hobbit", ISBN: "9780000000001", AuthorID: tolkien.ID, PublisherID: allen.ID, Year: 1937, Genre: "Fantasy", Quantity: 1}
+	assert.NoError(t, db.Create(hobbit).Error)
+	dune := &models.Book{Title: "Dune", ISBN: "9780000000002", AuthorID: herbert.ID, PublisherID: chilton.ID, Year: 1965, Genre: "Science Fiction", Quantity: 1}
+	assert.NoError(t, db.Create(dune).Error)
+	messiah := &models.Book{Title: "Dune Messiah", ISBN: "9780000000003", AuthorID: herbert.ID, PublisherID: allen.ID, Year: 1969, Genre: "Science Fiction", Quantity: 1}
+	assert.NoError(t, db.Create(messiah).Error)
+
+	// Matches across author, publisher and genre
+	results, total, err := repo.SearchBooks(ctx, "tolkien", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, hobbit.ID, results[0].Book.ID)
+	assert.Equal(t, "J.R.R. Tolkien", results[0].Book.Author.Name)
+
+	_, total, err = repo.SearchBooks(ctx, "unwin", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	// Results come best match first with the matched terms highlighted
+	results, total, err = repo.SearchBooks(ctx, "dune OR fiction", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.GreaterOrEqual(t, results[0].Rank, results[1].Rank)
+	assert.Contains(t, results[0].Highlight, "<mark>Dune</mark>")
+
+	// Paging keeps the total
+	results, total, err = repo.SearchBooks(ctx, "herbert", 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, results, 1)
+
+	// Renaming an author refreshes their books' search vectors
+	assert.NoError(t, db.Model(tolkien).Update("name", "John Ronald Reuel Tolkien").Error)
+	_, total, err = repo.SearchBooks(ctx, "ronald", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	results, total, err = repo.SearchBooks(ctx, "nonexistentterm", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.Empty(t, results)
+}
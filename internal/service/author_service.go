@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+)
+
+type AuthorService interface {
+	CreateAuthor(ctx context.Context, author *models.Author) error
+	UpdateAuthor(ctx context.Context, author *models.Author) error
+	DeleteAuthor(ctx context.Context, id uuid.UUID) error
+	GetAuthor(ctx context.Context, id uuid.UUID) (*models.Author, error)
+	ListAuthors(ctx context.Context, page, limit int) ([]models.Author, int64, error)
+	ListAuthorBooks(ctx context.Context, id uuid.UUID, page, limit int) ([]models.Book, int64, error)
+}
+
+type authorService struct {
+	repo repository.AuthorRepository
+}
+
+func NewAuthorService(repo repository.AuthorRepository) AuthorService {
+	return &authorService{repo: repo}
+}
+
+func (s *authorService) CreateAuthor(ctx context.Context, author *models.Author) error {
+	return s.repo.Create(ctx, author)
+}
+
+func (s *authorService) UpdateAuthor(ctx context.Context, author *models.Author) error {
+	return s.repo.Update(ctx, author)
+}
+
+func (s *authorService) DeleteAuthor(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *authorService) GetAuthor(ctx context.Context, id uuid.UUID) (*models.Author, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *authorService) ListAuthors(ctx context.Context, page, limit int) ([]models.Author, int64, error) {
+	return s.repo.List(ctx, page, limit)
+}
+
+func (s *authorService) ListAuthorBooks(ctx context.Context, id uuid.UUID, page, limit int) ([]models.Book, int64, error) {
+	// Make sure the author exists so an unknown ID is not reported as an empty list
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListBooks(ctx, id, page, limit)
+}
@@ -1,66 +1,131 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/isbn"
 	"github.com/library-api/internal/models"
 	"github.com/library-api/internal/repository"
 )
 
+// BookService manages the catalogue and circulation of books. ctx is
+// passed on to the repository, so cancelling it abandons the queries.
 type BookService interface {
-	CreateBook(book *models.Book) error
-	UpdateBook(book *models.Book) error
-	DeleteBook(id uuid.UUID) error
-	GetBook(id uuid.UUID) (*models.Book, error)
-	ListBooks(page, limit int) ([]models.Book, int64, error)
-	IssueBook(id uuid.UUID) (*models.Book, error)
-	ReturnBook(id uuid.UUID) (*models.Book, error)
+	CreateBook(ctx context.Context, book *models.Book) error
+	UpdateBook(ctx context.Context, book *models.Book) error
+	DeleteBook(ctx context.Context, id uuid.UUID) error
+	GetBook(ctx context.Context, id uuid.UUID) (*models.Book, error)
+	// GetBookByISBN accepts an ISBN-10 or ISBN-13, with or without hyphens
+	GetBookByISBN(ctx context.Context, number string) (*models.Book, error)
+	ListBooks(ctx context.Context, filter repository.BookFilter, page, limit int) ([]models.Book, int64, error)
+	ListBooksByCursor(ctx context.Context, filter repository.BookFilter, cursor *repository.BookCursor, limit int, withTotal bool) (*repository.BookPage, error)
+	IssueBook(ctx context.Context, id, memberID uuid.UUID) (*models.Book, error)
+	ReturnBook(ctx context.Context, id, memberID uuid.UUID) (*models.Book, error)
+	RenewBook(ctx context.Context, id, memberID uuid.UUID) (*models.Loan, error)
 }
 
 type bookService struct {
-	repo repository.BookRepository
+	repo     repository.BookRepository
+	policy   CirculationPolicy
+	recorder CirculationRecorder
+	logger   *slog.Logger
 }
 
-func NewBookService(repo repository.BookRepository) BookService {
-	return &bookService{repo: repo}
+// NewBookService returns a BookService; recorder may be nil
+func NewBookService(repo repository.BookRepository, policy CirculationPolicy, recorder CirculationRecorder, logger *slog.Logger) BookService {
+	if recorder == nil {
+		recorder = nopRecorder{}
+	}
+	return &bookService{repo: repo, policy: policy, recorder: recorder, logger: logger}
+}
+
+func (s *bookService) CreateBook(ctx context.Context, book *models.Book) error {
+	if err := normalizeISBN(book); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, book)
 }
 
-func (s *bookService) CreateBook(book *models.Book) error {
-	return s.repo.Create(book)
+func (s *bookService) UpdateBook(ctx context.Context, book *models.Book) error {
+	if err := normalizeISBN(book); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, book)
 }
 
-func (s *bookService) UpdateBook(book *models.Book) error {
-	if book.Quantity < book.QuantityIssued {
-		return fmt.Errorf("quantity cannot be less than the number of issued copies")
+// normalizeISBN stores every ISBN as 13 bare digits so lookups and the
+// unique index see one form
+func normalizeISBN(book *models.Book) error {
+	normalized, err := isbn.Normalize(book.ISBN)
+	if err != nil {
+		return apperr.Validation("invalid ISBN %q: %v", book.ISBN, err)
 	}
-	return s.repo.Update(book)
+	book.ISBN = normalized
+	return nil
+}
+
+func (s *bookService) DeleteBook(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
 }
 
-func (s *bookService) DeleteBook(id uuid.UUID) error {
-	return s.repo.Delete(id)
+func (s *bookService) GetBook(ctx context.Context, id uuid.UUID) (*models.Book, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *bookService) GetBook(id uuid.UUID) (*models.Book, error) {
-	return s.repo.GetByID(id)
+func (s *bookService) GetBookByISBN(ctx context.Context, number string) (*models.Book, error) {
+	normalized, err := isbn.Normalize(number)
+	if err != nil {
+		return nil, apperr.Validation("invalid ISBN %q: %v", number, err)
+	}
+	return s.repo.GetByISBN(ctx, normalized)
+}
+
+func (s *bookService) ListBooks(ctx context.Context, filter repository.BookFilter, page, limit int) ([]models.Book, int64, error) {
+	return s.repo.List(ctx, filter, page, limit)
 }
 
-func (s *bookService) ListBooks(page, limit int) ([]models.Book, int64, error) {
-	return s.repo.List(page, limit)
+func (s *bookService) ListBooksByCursor(ctx context.Context, filter repository.BookFilter, cursor *repository.BookCursor, limit int, withTotal bool) (*repository.BookPage, error) {
+	return s.repo.ListByCursor(ctx, filter, cursor, limit, withTotal)
 }
 
-func (s *bookService) IssueBook(id uuid.UUID) (*models.Book, error) {
-	book, err := s.repo.IssueBook(id)
+func (s *bookService) IssueBook(ctx context.Context, id, memberID uuid.UUID) (*models.Book, error) {
+	dueAt := time.Now().Add(s.policy.LoanPeriod)
+	book, err := s.repo.IssueBook(ctx, id, memberID, dueAt, s.policy.FineBlockThresholdCents)
 	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNoAvailableCopies):
+			s.recorder.IssueRejected(RejectUnavailable)
+		case errors.Is(err, repository.ErrFinesOwed):
+			s.recorder.IssueRejected(RejectFinesOwed)
+		}
 		return nil, fmt.Errorf("failed to issue book: %w", err)
 	}
+	s.recorder.BookIssued()
+	s.logger.InfoContext(ctx, "book issued", "book_id", id, "member_id", memberID, "due_at", dueAt)
 	return book, nil
 }
 
-func (s *bookService) ReturnBook(id uuid.UUID) (*models.Book, error) {
-	book, err := s.repo.ReturnBook(id)
+func (s *bookService) ReturnBook(ctx context.Context, id, memberID uuid.UUID) (*models.Book, error) {
+	book, err := s.repo.ReturnBook(ctx, id, memberID, s.policy.HoldPickupWindow)
 	if err != nil {
 		return nil, fmt.Errorf("failed to return book: %w", err)
 	}
+	s.recorder.BookReturned()
+	s.logger.InfoContext(ctx, "book returned", "book_id", id, "member_id", memberID)
 	return book, nil
 }
+
+func (s *bookService) RenewBook(ctx context.Context, id, memberID uuid.UUID) (*models.Loan, error) {
+	loan, err := s.repo.RenewBook(ctx, id, memberID, s.policy.LoanPeriod, s.policy.MaxRenewals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew book: %w", err)
+	}
+	s.logger.InfoContext(ctx, "loan renewed", "loan_id", loan.ID, "book_id", id, "member_id", memberID, "due_at", loan.DueAt)
+	return loan, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans this package starts
+const tracerName = "github.com/library-api/internal/service"
+
+// tracedBookService records a span for every BookService call
+type tracedBookService struct {
+	next   BookService
+	tracer trace.Tracer
+}
+
+// NewTracedBookService wraps next so each call is traced with a tracer from
+// provider
+func NewTracedBookService(next BookService, provider trace.TracerProvider) BookService {
+	return &tracedBookService{next: next, tracer: provider.Tracer(tracerName)}
+}
+
+// start begins a span for method as a child of the span in ctx, and returns
+// the context carrying it for the wrapped call
+func (s *tracedBookService) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "BookService."+method, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on span and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func bookAttr(id uuid.UUID) attribute.KeyValue   { return attribute.String("book.id", id.String()) }
+func memberAttr(id uuid.UUID) attribute.KeyValue { return attribute.String("member.id", id.String()) }
+
+func (s *tracedBookService) CreateBook(ctx context.Context, book *models.Book) (err error) {
+	ctx, span := s.start(ctx, "CreateBook")
+	defer func() { endSpan(span, err) }()
+	return s.next.CreateBook(ctx, book)
+}
+
+func (s *tracedBookService) UpdateBook(ctx context.Context, book *models.Book) (err error) {
+	ctx, span := s.start(ctx, "UpdateBook", bookAttr(book.ID))
+	defer func() { endSpan(span, err) }()
+	return s.next.UpdateBook(ctx, book)
+}
+
+func (s *tracedBookService) DeleteBook(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteBook", bookAttr(id))
+	defer func() { endSpan(span, err) }()
+	return s.next.DeleteBook(ctx, id)
+}
+
+func (s *tracedBookService) GetBook(ctx context.Context, id uuid.UUID) (_ *models.Book, err error) {
+	ctx, span := s.start(ctx, "GetBook", bookAttr(id))
+	defer func() { endSpan(span, err) }()
+	return s.next.GetBook(ctx, id)
+}
+
+func (s *tracedBookService) GetBookByISBN(ctx context.Context, number string) (_ *models.Book, err error) {
+	ctx, span := s.start(ctx, "GetBookByISBN")
+	defer func() { endSpan(span, err) }()
+	return s.next.GetBookByISBN(ctx, number)
+}
+
+func (s *tracedBookService) ListBooks(ctx context.Context, filter repository.BookFilter, page, limit int) (_ []models.Book, _ int64, err error) {
+	ctx, span := s.start(ctx, "ListBooks", attribute.Int("page", page), attribute.Int("limit", limit))
+	defer func() { endSpan(span, err) }()
+	return s.next.ListBooks(ctx, filter, page, limit)
+}
+
+func (s *tracedBookService) ListBooksByCursor(ctx context.Context, filter repository.BookFilter, cursor *repository.BookCursor, limit int, withTotal bool) (_ *repository.BookPage, err error) {
+	ctx, span := s.start(ctx, "ListBooksByCursor", attribute.Int("limit", limit))
+	defer func() { endSpan(span, err) }()
+	return s.next.ListBooksByCursor(ctx, filter, cursor, limit, withTotal)
+}
+
+func (s *tracedBookService) IssueBook(ctx context.Context, id, memberID uuid.UUID) (_ *models.Book, err error) {
+	ctx, span := s.start(ctx, "IssueBook", bookAttr(id), memberAttr(memberID))
+	defer func() { endSpan(span, err) }()
+	return s.next.IssueBook(ctx, id, memberID)
+}
+
+func (s *tracedBookService) ReturnBook(ctx context.Context, id, memberID uuid.UUID) (_ *models.Book, err error) {
+	ctx, span := s.start(ctx, "ReturnBook", bookAttr(id), memberAttr(memberID))
+	defer func() { endSpan(span, err) }()
+	return s.next.ReturnBook(ctx, id, memberID)
+}
+
+func (s *tracedBookService) RenewBook(ctx context.Context, id, memberID uuid.UUID) (_ *models.Loan, err error) {
+	ctx, span := s.start(ctx, "RenewBook", bookAttr(id), memberAttr(memberID))
+	defer func() { endSpan(span, err) }()
+	return s.next.RenewBook(ctx, id, memberID)
+}
@@ -0,0 +1,53 @@
+package service
+
+import "time"
+
+// CirculationPolicy holds the lending rules applied to loans, holds and fines
+type CirculationPolicy struct {
+	// LoanPeriod is how long a member may keep an issued copy
+	LoanPeriod time.Duration
+	// MaxRenewals is how many times a loan may be extended by LoanPeriod
+	MaxRenewals int
+	// HoldPickupWindow is how long a copy is set aside for a ready hold
+	HoldPickupWindow time.Duration
+	// FineDailyRateCents is charged for every full day a loan is overdue
+	FineDailyRateCents int64
+	// FineCapCents is the most a single overdue loan can be fined
+	FineCapCents int64
+	// FineBlockThresholdCents is the unpaid balance above which a member
+	// may not borrow
+	FineBlockThresholdCents int64
+}
+
+// DefaultCirculationPolicy returns the policy used when nothing is configured
+func DefaultCirculationPolicy() CirculationPolicy {
+	return CirculationPolicy{
+		LoanPeriod:              14 * 24 * time.Hour,
+		MaxRenewals:             2,
+		HoldPickupWindow:        3 * 24 * time.Hour,
+		FineDailyRateCents:      25,
+		FineCapCents:            1000,
+		FineBlockThresholdCents: 500,
+	}
+}
+
+// Reasons an issue is refused, as passed to CirculationRecorder.IssueRejected
+const (
+	RejectUnavailable = "unavailable"
+	RejectFinesOwed   = "fines_owed"
+)
+
+// CirculationRecorder is told the outcome of each circulation request, e.g.
+// to count them
+type CirculationRecorder interface {
+	BookIssued()
+	BookReturned()
+	IssueRejected(reason string)
+}
+
+// nopRecorder is used when no CirculationRecorder is given
+type nopRecorder struct{}
+
+func (nopRecorder) BookIssued()          {}
+func (nopRecorder) BookReturned()        {}
+func (nopRecorder) IssueRejected(string) {}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+)
+
+type FineService interface {
+	GetFine(ctx context.Context, id uuid.UUID) (*models.Fine, error)
+	ListMemberFines(ctx context.Context, memberID uuid.UUID) ([]models.Fine, int64, error)
+	PayFine(ctx context.Context, id uuid.UUID, amountCents int64, note string) (*models.Fine, error)
+	WaiveFine(ctx context.Context, id uuid.UUID, amountCents int64, reason string) (*models.Fine, error)
+	AccrueFines(ctx context.Context) (int, error)
+}
+
+type fineService struct {
+	repo       repository.FineRepository
+	memberRepo repository.MemberRepository
+	policy     CirculationPolicy
+	logger     *slog.Logger
+}
+
+func NewFineService(repo repository.FineRepository, memberRepo repository.MemberRepository, policy CirculationPolicy, logger *slog.Logger) FineService {
+	return &fineService{repo: repo, memberRepo: memberRepo, policy: policy, logger: logger}
+}
+
+func (s *fineService) GetFine(ctx context.Context, id uuid.UUID) (*models.Fine, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListMemberFines returns a member's fines along with their total unpaid balance
+func (s *fineService) ListMemberFines(ctx context.Context, memberID uuid.UUID) ([]models.Fine, int64, error) {
+	if _, err := s.memberRepo.GetByID(ctx, memberID); err != nil {
+		return nil, 0, err
+	}
+
+	fines, err := s.repo.ListByMember(ctx, memberID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	balance, err := s.repo.OutstandingBalance(ctx, memberID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return fines, balance, nil
+}
+
+func (s *fineService) PayFine(ctx context.Context, id uuid.UUID, amountCents int64, note string) (*models.Fine, error) {
+	fine, err := s.repo.Pay(ctx, id, amountCents, note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pay fine: %w", err)
+	}
+	s.logger.InfoContext(ctx, "fine paid", "fine_id", id, "amount_cents", amountCents)
+	return fine, nil
+}
+
+func (s *fineService) WaiveFine(ctx context.Context, id uuid.UUID, amountCents int64, reason string) (*models.Fine, error) {
+	fine, err := s.repo.Waive(ctx, id, amountCents, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to waive fine: %w", err)
+	}
+	s.logger.InfoContext(ctx, "fine waived", "fine_id", id, "amount_cents", amountCents, "reason", reason)
+	return fine, nil
+}
+
+// AccrueFines marks overdue loans and charges the configured daily rate. It
+// is meant to be run periodically.
+func (s *fineService) AccrueFines(ctx context.Context) (int, error) {
+	n, err := s.repo.AccrueFines(ctx, time.Now(), s.policy.FineDailyRateCents, s.policy.FineCapCents)
+	if err != nil {
+		return n, err
+	}
+	if n > 0 {
+		s.logger.InfoContext(ctx, "fines accrued", "fines", n)
+	}
+	return n, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+)
+
+type HoldService interface {
+	PlaceHold(ctx context.Context, bookID, memberID uuid.UUID) (*models.Hold, error)
+	CancelHold(ctx context.Context, id uuid.UUID) (*models.Hold, error)
+	GetHold(ctx context.Context, id uuid.UUID) (*models.Hold, error)
+	ListBookHolds(ctx context.Context, bookID uuid.UUID) ([]models.Hold, error)
+	ListMemberHolds(ctx context.Context, memberID uuid.UUID) ([]models.Hold, error)
+	ProcessHolds(ctx context.Context) (int, error)
+}
+
+type holdService struct {
+	repo       repository.HoldRepository
+	bookRepo   repository.BookRepository
+	memberRepo repository.MemberRepository
+	policy     CirculationPolicy
+	logger     *slog.Logger
+}
+
+func NewHoldService(repo repository.HoldRepository, bookRepo repository.BookRepository, memberRepo repository.MemberRepository, policy CirculationPolicy, logger *slog.Logger) HoldService {
+	return &holdService{repo: repo, bookRepo: bookRepo, memberRepo: memberRepo, policy: policy, logger: logger}
+}
+
+func (s *holdService) PlaceHold(ctx context.Context, bookID, memberID uuid.UUID) (*models.Hold, error) {
+	hold, err := s.repo.PlaceHold(ctx, bookID, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place hold: %w", err)
+	}
+	return hold, nil
+}
+
+func (s *holdService) CancelHold(ctx context.Context, id uuid.UUID) (*models.Hold, error) {
+	hold, err := s.repo.CancelHold(ctx, id, s.policy.HoldPickupWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel hold: %w", err)
+	}
+	return hold, nil
+}
+
+func (s *holdService) GetHold(ctx context.Context, id uuid.UUID) (*models.Hold, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *holdService) ListBookHolds(ctx context.Context, bookID uuid.UUID) ([]models.Hold, error) {
+	if _, err := s.bookRepo.GetByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByBook(ctx, bookID)
+}
+
+func (s *holdService) ListMemberHolds(ctx context.Context, memberID uuid.UUID) ([]models.Hold, error) {
+	if _, err := s.memberRepo.GetByID(ctx, memberID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByMember(ctx, memberID)
+}
+
+// ProcessHolds expires uncollected holds and passes their copies down the
+// queue. It is meant to be run periodically.
+func (s *holdService) ProcessHolds(ctx context.Context) (int, error) {
+	n, err := s.repo.ProcessHolds(ctx, s.policy.HoldPickupWindow)
+	if err != nil {
+		return n, err
+	}
+	if n > 0 {
+		s.logger.InfoContext(ctx, "uncollected holds expired", "holds", n)
+	}
+	return n, nil
+}
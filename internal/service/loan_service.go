@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+)
+
+type LoanService interface {
+	GetLoan(ctx context.Context, id uuid.UUID) (*models.Loan, error)
+	ListBookLoans(ctx context.Context, bookID uuid.UUID, status string, page, limit int) ([]models.Loan, int64, error)
+	ListMemberLoans(ctx context.Context, memberID uuid.UUID, status string, page, limit int) ([]models.Loan, int64, error)
+}
+
+type loanService struct {
+	repo       repository.LoanRepository
+	bookRepo   repository.BookRepository
+	memberRepo repository.MemberRepository
+}
+
+func NewLoanService(repo repository.LoanRepository, bookRepo repository.BookRepository, memberRepo repository.MemberRepository) LoanService {
+	return &loanService{repo: repo, bookRepo: bookRepo, memberRepo: memberRepo}
+}
+
+func (s *loanService) GetLoan(ctx context.Context, id uuid.UUID) (*models.Loan, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *loanService) ListBookLoans(ctx context.Context, bookID uuid.UUID, status string, page, limit int) ([]models.Loan, int64, error) {
+	if _, err := s.bookRepo.GetByID(ctx, bookID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(ctx, repository.LoanFilter{BookID: bookID, Status: status}, page, limit)
+}
+
+func (s *loanService) ListMemberLoans(ctx context.Context, memberID uuid.UUID, status string, page, limit int) ([]models.Loan, int64, error) {
+	if _, err := s.memberRepo.GetByID(ctx, memberID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(ctx, repository.LoanFilter{MemberID: memberID, Status: status}, page, limit)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+)
+
+type MemberService interface {
+	CreateMember(ctx context.Context, member *models.Member) error
+	UpdateMember(ctx context.Context, member *models.Member) error
+	DeleteMember(ctx context.Context, id uuid.UUID) error
+	GetMember(ctx context.Context, id uuid.UUID) (*models.Member, error)
+	// GetMemberBySubject finds the member linked to a token subject
+	GetMemberBySubject(ctx context.Context, subject string) (*models.Member, error)
+	ListMembers(ctx context.Context, page, limit int) ([]models.Member, int64, error)
+}
+
+type memberService struct {
+	repo repository.MemberRepository
+}
+
+func NewMemberService(repo repository.MemberRepository) MemberService {
+	return &memberService{repo: repo}
+}
+
+func (s *memberService) CreateMember(ctx context.Context, member *models.Member) error {
+	return s.repo.Create(ctx, member)
+}
+
+func (s *memberService) UpdateMember(ctx context.Context, member *models.Member) error {
+	return s.repo.Update(ctx, member)
+}
+
+func (s *memberService) DeleteMember(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *memberService) GetMember(ctx context.Context, id uuid.UUID) (*models.Member, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *memberService) GetMemberBySubject(ctx context.Context, subject string) (*models.Member, error) {
+	return s.repo.GetBySubject(ctx, subject)
+}
+
+func (s *memberService) ListMembers(ctx context.Context, page, limit int) ([]models.Member, int64, error) {
+	return s.repo.List(ctx, page, limit)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/library-api/internal/apperr"
+	"github.com/library-api/internal/models"
+	"github.com/library-api/internal/repository"
+)
+
+type PublisherService interface {
+	CreatePublisher(ctx context.Context, publisher *models.Publisher) error
+	UpdatePublisher(ctx context.Context, publisher *models.Publisher) error
+	DeletePublisher(ctx context.Context, id uuid.UUID) error
+	GetPublisher(ctx context.Context, id uuid.UUID) (*models.Publisher, error)
+	ListPublishers(ctx context.Context, page, limit int) ([]models.Publisher, int64, error)
+	ListImprints(ctx context.Context, id uuid.UUID) ([]models.Publisher, error)
+	ListPublisherBooks(ctx context.Context, id uuid.UUID, includeImprints bool, page, limit int) ([]models.Book, int64, error)
+}
+
+type publisherService struct {
+	repo repository.PublisherRepository
+}
+
+func NewPublisherService(repo repository.PublisherRepository) PublisherService {
+	return &publisherService{repo: repo}
+}
+
+func (s *publisherService) CreatePublisher(ctx context.Context, publisher *models.Publisher) error {
+	if publisher.ParentID != nil {
+		if _, err := s.repo.GetByID(ctx, *publisher.ParentID); err != nil {
+			return parentLookupError(err)
+		}
+	}
+	return s.repo.Create(ctx, publisher)
+}
+
+func (s *publisherService) UpdatePublisher(ctx context.Context, publisher *models.Publisher) error {
+	if publisher.ParentID != nil {
+		if err := s.checkParent(ctx, publisher.ID, *publisher.ParentID); err != nil {
+			return err
+		}
+	}
+	return s.repo.Update(ctx, publisher)
+}
+
+// checkParent makes sure parentID exists and that making it the parent of id
+// would not introduce a cycle in the imprint hierarchy.
+func (s *publisherService) checkParent(ctx context.Context, id, parentID uuid.UUID) error {
+	if id == parentID {
+		return apperr.Validation("publisher cannot be its own parent")
+	}
+
+	if _, err := s.repo.GetByID(ctx, parentID); err != nil {
+		return parentLookupError(err)
+	}
+
+	descendants, err := s.repo.DescendantIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, d := range descendants {
+		if d == parentID {
+			return apperr.Validation("publisher cannot be an imprint of its own imprint")
+		}
+	}
+	return nil
+}
+
+// parentLookupError reports a missing parent as bad input rather than as the
+// publisher being updated not existing
+func parentLookupError(err error) error {
+	if errors.Is(err, apperr.ErrNotFound) {
+		return apperr.Validation("parent publisher not found")
+	}
+	return err
+}
+
+func (s *publisherService) DeletePublisher(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *publisherService) GetPublisher(ctx context.Context, id uuid.UUID) (*models.Publisher, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *publisherService) ListPublishers(ctx context.Context, page, limit int) ([]models.Publisher, int64, error) {
+	return s.repo.List(ctx, page, limit)
+}
+
+func (s *publisherService) ListImprints(ctx context.Context, id uuid.UUID) ([]models.Publisher, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListImprints(ctx, id)
+}
+
+func (s *publisherService) ListPublisherBooks(ctx context.Context, id uuid.UUID, includeImprints bool, page, limit int) ([]models.Book, int64, error) {
+	// Make sure the publisher exists so an unknown ID is not reported as an empty list
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListBooks(ctx, id, includeImprints, page, limit)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/library-api/internal/repository"
+)
+
+type SearchService interface {
+	SearchBooks(ctx context.Context, query string, page, limit int) ([]repository.BookSearchResult, int64, error)
+}
+
+type searchService struct {
+	repo repository.SearchRepository
+}
+
+func NewSearchService(repo repository.SearchRepository) SearchService {
+	return &searchService{repo: repo}
+}
+
+func (s *searchService) SearchBooks(ctx context.Context, query string, page, limit int) ([]repository.BookSearchResult, int64, error) {
+	return s.repo.SearchBooks(ctx, query, page, limit)
+}
@@ -0,0 +1,69 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/auth"
+	"github.com/library-api/internal/logging"
+	"github.com/library-api/internal/repository"
+	"github.com/library-api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyAuthenticationIntegration(t *testing.T) {
+	ctx := context.Background()
+	clearTables()
+
+	keys := service.NewAPIKeyService(repository.NewAPIKeyRepository(testDB), logging.Discard())
+	verifier := auth.NewVerifier(auth.VerifierConfig{Keys: auth.HMACKeySet([]byte("unused-secret-for-api-key-tests!!"))})
+
+	r := gin.New()
+	r.Use(auth.Middleware(verifier, keys))
+	r.GET("/books", auth.Require(auth.PermCatalogRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.DELETE("/books", auth.Require(auth.PermCatalogDelete), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	call := func(method, key string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/books", nil)
+		req.Header.Set(auth.APIKeyHeader, key)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	created, plaintext, err := keys.CreateAPIKey(ctx, "Kiosk", []string{"catalog:read"}, nil, "admin")
+	require.NoError(t, err)
+	assert.NotEqual(t, plaintext, created.Hash, "only the hash is stored")
+
+	assert.Equal(t, http.StatusOK, call("GET", plaintext))
+	assert.Equal(t, http.StatusForbidden, call("DELETE", plaintext), "scopes limit what a key can do")
+	assert.Equal(t, http.StatusUnauthorized, call("GET", plaintext+"x"))
+	assert.Equal(t, http.StatusUnauthorized, call("GET", "not-a-key"))
+
+	used, err := keys.GetAPIKey(ctx, created.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, used.LastUsedAt)
+
+	_, err = keys.RevokeAPIKey(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, call("GET", plaintext))
+
+	// Expired keys are refused
+	expiresAt := time.Now().Add(time.Hour)
+	expiring, plaintext, err := keys.CreateAPIKey(ctx, "Importer", []string{"catalog:read"}, &expiresAt, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, call("GET", plaintext))
+
+	err = testDB.Model(expiring).Update("expires_at", time.Now().Add(-time.Minute)).Error
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, call("GET", plaintext))
+}
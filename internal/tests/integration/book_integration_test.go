@@ -2,16 +2,22 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/library-api/internal/api"
+	"github.com/library-api/internal/isbn"
+	"github.com/library-api/internal/logging"
+	"github.com/library-api/internal/migrations"
 	"github.com/library-api/internal/models"
 	"github.com/library-api/internal/repository"
 	"github.com/library-api/internal/service"
@@ -36,21 +42,25 @@ func TestMain(m *testing.M) {
 	}
 
 	// Migrate the schema
-	err = testDB.AutoMigrate(&models.Author{}, &models.Publisher{}, &models.Book{})
+	migrator, err := migrations.New(testDB)
+	if err == nil {
+		_, err = migrator.Up()
+	}
 	if err != nil {
 		fmt.Println("Failed to migrate:", err)
 		os.Exit(1)
 	}
 
 	// Set up repository, service, handler
-	bookRepo := repository.NewBookRepository(testDB)
-	bookService := service.NewBookService(bookRepo)
+	bookRepo := repository.NewBookRepository(testDB, 0)
+	bookService := service.NewBookService(bookRepo, service.DefaultCirculationPolicy(), nil, logging.Discard())
 	bookHandler := api.NewBookHandler(bookService)
 
 	router = gin.Default()
 	router.GET("/books", bookHandler.ListBooks)
 	router.POST("/books", bookHandler.CreateBook)
 	router.GET("/books/:id", bookHandler.GetBook)
+	router.GET("/books/isbn/:isbn", bookHandler.GetBookByISBN)
 	router.PUT("/books/:id", bookHandler.UpdateBook)
 	router.DELETE("/books/:id", bookHandler.DeleteBook)
 	router.POST("/books/:id/issue", bookHandler.IssueBook)
@@ -62,14 +72,26 @@ func TestMain(m *testing.M) {
 
 // Helper to clear tables before test
 func clearTables() {
+	testDB.Exec("TRUNCATE TABLE api_keys RESTART IDENTITY CASCADE;")
+	testDB.Exec("TRUNCATE TABLE fine_entries RESTART IDENTITY CASCADE;")
+	testDB.Exec("TRUNCATE TABLE fines RESTART IDENTITY CASCADE;")
+	testDB.Exec("TRUNCATE TABLE holds RESTART IDENTITY CASCADE;")
+	testDB.Exec("TRUNCATE TABLE loans RESTART IDENTITY CASCADE;")
+	testDB.Exec("TRUNCATE TABLE members RESTART IDENTITY CASCADE;")
 	testDB.Exec("TRUNCATE TABLE books RESTART IDENTITY CASCADE;")
 	testDB.Exec("TRUNCATE TABLE authors RESTART IDENTITY CASCADE;")
 	testDB.Exec("TRUNCATE TABLE publishers RESTART IDENTITY CASCADE;")
 }
 
-// helper to create unique ISBN
+// helper to create unique ISBN with a valid check digit
 func uniqueISBN() string {
-	return fmt.Sprintf("%013d", time.Now().UnixNano()%1_000_000_000_0000)
+	body := fmt.Sprintf("978%09d", time.Now().UnixNano()%1_000_000_000)
+	for d := 0; d <= 9; d++ {
+		if candidate := body + strconv.Itoa(d); isbn.Valid(candidate) {
+			return candidate
+		}
+	}
+	panic("unreachable")
 }
 
 // Helper to create a test book with valid author and publisher
@@ -97,6 +119,17 @@ func createTestBook(t *testing.T) models.Book {
 	return book
 }
 
+// Helper to create a test member who can borrow books
+func createTestMember(t *testing.T) models.Member {
+	member := models.Member{
+		Name:  "Integration Member",
+		Email: fmt.Sprintf("member-%d@example.com", time.Now().UnixNano()),
+	}
+	err := testDB.Create(&member).Error
+	assert.NoError(t, err)
+	return member
+}
+
 func TestBookCRUDIntegration(t *testing.T) {
 	clearTables()
 	// Create author and publisher first
@@ -173,10 +206,13 @@ func TestBookCRUDIntegration(t *testing.T) {
 func TestIssueAndReturnIntegration(t *testing.T) {
 	book := createTestBook(t)
 	assert.Equal(t, 0, book.QuantityIssued)
+	member := createTestMember(t)
+	loanBody, _ := json.Marshal(api.IssueBookRequest{MemberID: member.ID})
 
 	// Issue
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/books/"+book.ID.String()+"/issue", nil)
+	req, _ := http.NewRequest("POST", "/books/"+book.ID.String()+"/issue", bytes.NewBuffer(loanBody))
+	req.Header.Set("Content-Type", "application/json")
 	router.ServeHTTP(w, req)
 	assert.Equal(t, 200, w.Code)
 
@@ -187,7 +223,8 @@ func TestIssueAndReturnIntegration(t *testing.T) {
 
 	// Return
 	w = httptest.NewRecorder()
-	req, _ = http.NewRequest("POST", "/books/"+book.ID.String()+"/return", nil)
+	req, _ = http.NewRequest("POST", "/books/"+book.ID.String()+"/return", bytes.NewBuffer(loanBody))
+	req.Header.Set("Content-Type", "application/json")
 	router.ServeHTTP(w, req)
 	assert.Equal(t, 200, w.Code)
 
@@ -196,3 +233,109 @@ func TestIssueAndReturnIntegration(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 0, returned.QuantityIssued)
 }
+
+func TestIssueBlockedByUnpaidFinesIntegration(t *testing.T) {
+	book := createTestBook(t)
+	member := createTestMember(t)
+
+	// An old, unreturned loan puts the member over the fine threshold
+	loan := models.Loan{
+		BookID:   book.ID,
+		MemberID: member.ID,
+		IssuedAt: time.Now().Add(-60 * 24 * time.Hour),
+		DueAt:    time.Now().Add(-46 * 24 * time.Hour),
+	}
+	err := testDB.Create(&loan).Error
+	assert.NoError(t, err)
+
+	_, err = repository.NewFineRepository(testDB).AccrueFines(context.Background(), time.Now(), 25, 1000)
+	assert.NoError(t, err)
+
+	body, _ := json.Marshal(api.IssueBookRequest{MemberID: member.ID})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/books/"+book.ID.String()+"/issue", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestBookErrorStatusesIntegration(t *testing.T) {
+	clearTables()
+	book := createTestBook(t)
+	member := createTestMember(t)
+
+	// Duplicate ISBN
+	reqBody := api.CreateBookRequest{
+		Title:       "Duplicate",
+		ISBN:        book.ISBN,
+		AuthorID:    book.AuthorID,
+		PublisherID: book.PublisherID,
+		Year:        2025,
+		Genre:       "Fiction",
+		Quantity:    1,
+	}
+	body, _ := json.Marshal(reqBody)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code)
+
+	// Unknown author
+	reqBody.ISBN = uniqueISBN()
+	reqBody.AuthorID = uuid.New()
+	body, _ = json.Marshal(reqBody)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/books", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+
+	// Issuing a book that does not exist
+	body, _ = json.Marshal(api.IssueBookRequest{MemberID: member.ID})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/books/"+uuid.New().String()+"/issue", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestGetBookByISBNIntegration(t *testing.T) {
+	clearTables()
+	author := models.Author{Name: "ISBN Author"}
+	assert.NoError(t, testDB.Create(&author).Error)
+	publisher := models.Publisher{Name: "ISBN Publisher"}
+	assert.NoError(t, testDB.Create(&publisher).Error)
+
+	// Created from a hyphenated ISBN-10, stored as ISBN-13
+	body, _ := json.Marshal(api.CreateBookRequest{
+		Title:       "Computer Networks",
+		ISBN:        "0-306-40615-2",
+		AuthorID:    author.ID,
+		PublisherID: publisher.ID,
+		Year:        2025,
+		Genre:       "Computing",
+		Quantity:    1,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code)
+
+	var created models.Book
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "9780306406157", created.ISBN)
+
+	for _, number := range []string{"0306406152", "978-0-306-40615-7"} {
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/books/isbn/"+number, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code, number)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/books/isbn/9780306406158", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+}
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"gorm.io/gorm"
+)
+
+// NewGormPlugin returns a GORM plugin that starts a client span for every
+// query run inside a traced operation. Queries without a parent span, such
+// as migrations and background jobs, are not traced. Spans record the SQL
+// with its bound values masked.
+func NewGormPlugin(provider trace.TracerProvider) gorm.Plugin {
+	return otelgorm.NewPlugin(
+		otelgorm.WithTracerProvider(childOnlyProvider{provider: provider}),
+		otelgorm.WithoutQueryVariables(),
+		// Connection pool statistics are exported as Prometheus metrics
+		otelgorm.WithoutMetrics(),
+	)
+}
+
+// childOnlyProvider hands out tracers that only start spans under a parent
+type childOnlyProvider struct {
+	embedded.TracerProvider
+	provider trace.TracerProvider
+}
+
+func (p childOnlyProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return childOnlyTracer{tracer: p.provider.Tracer(name, opts...)}
+}
+
+type childOnlyTracer struct {
+	embedded.Tracer
+	tracer trace.Tracer
+}
+
+// Start returns ctx and its non-recording span unchanged when ctx carries
+// no span
+func (t childOnlyTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return t.tracer.Start(ctx, name, opts...)
+}
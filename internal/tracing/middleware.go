@@ -0,0 +1,16 @@
+package tracing
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span named after the matched route for each
+// request, continuing the caller's trace when the request has a traceparent
+// header. Handlers find the span in the request context.
+func Middleware(provider trace.TracerProvider, serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName,
+		otelgin.WithTracerProvider(provider),
+		otelgin.WithPropagators(Propagator))
+}
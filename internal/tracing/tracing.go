@@ -0,0 +1,37 @@
+// Package tracing wires up OpenTelemetry: a tracer provider exporting over
+// OTLP/HTTP, server spans for Gin requests and client spans for GORM queries.
+// Spans are started from a trace.TracerProvider; the noop provider is how
+// tracing is disabled.
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Propagator reads and writes the W3C traceparent and tracestate headers
+var Propagator propagation.TextMapPropagator = propagation.TraceContext{}
+
+// NewProvider returns a tracer provider sending spans in batches to the
+// OTLP/HTTP collector whose base URL is endpoint. Traces started here are
+// recorded with probability sampleRatio; traces continued from a caller
+// follow the caller's decision. Shut the provider down to flush the last
+// batch.
+func NewProvider(ctx context.Context, endpoint, serviceName string, sampleRatio float64) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(strings.TrimSuffix(endpoint, "/")+"/v1/traces"))
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	), nil
+}
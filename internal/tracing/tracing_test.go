@@ -0,0 +1,148 @@
+package tracing_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/library-api/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// newProvider returns a provider recording every span in memory
+func newProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+// attr returns the value of the span attribute key, or an invalid value
+func attr(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, a := range span.Attributes() {
+		if string(a.Key) == key {
+			return a.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestNewProvider_Sampling(t *testing.T) {
+	provider, err := tracing.NewProvider(context.Background(), "http://localhost:4318", "library-api", 0)
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+	tracer := provider.Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "dropped")
+	assert.False(t, span.IsRecording())
+	span.End()
+
+	// A caller's sampling decision wins over the ratio
+	header := http.Header{"Traceparent": []string{traceparent}}
+	ctx := tracing.Propagator.Extract(context.Background(), propagation.HeaderCarrier(header))
+	_, span = tracer.Start(ctx, "kept")
+	assert.True(t, span.IsRecording())
+	span.End()
+}
+
+func TestNewProvider_Export(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+	}))
+	defer collector.Close()
+
+	provider, err := tracing.NewProvider(context.Background(), collector.URL+"/", "library-api", 1)
+	require.NoError(t, err)
+	_, span := provider.Tracer("test").Start(context.Background(), "work")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	r := <-received
+	assert.Equal(t, "/v1/traces", r.URL.Path)
+	assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider, recorder := newProvider()
+
+	r := gin.New()
+	r.Use(tracing.Middleware(provider, "library-api"))
+	r.GET("/books/:id", func(c *gin.Context) {
+		_, span := provider.Tracer("test").Start(c.Request.Context(), "work")
+		span.End()
+		c.Status(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest("GET", "/books/42", nil)
+	req.Header.Set("traceparent", traceparent)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	work, server := spans[0], spans[1]
+
+	assert.Equal(t, "/books/:id", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String(), "continues the caller's trace")
+	assert.Equal(t, "/books/:id", attr(server, "http.route").AsString())
+	assert.Equal(t, codes.Error, server.Status().Code)
+
+	assert.Equal(t, server.SpanContext().SpanID(), work.Parent().SpanID())
+}
+
+// unreachable is a database/sql connector whose connections always fail
+type unreachable struct{}
+
+func (unreachable) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("database unreachable")
+}
+
+func (unreachable) Driver() driver.Driver { return nil }
+
+func TestGormPlugin(t *testing.T) {
+	provider, recorder := newProvider()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(unreachable{})}),
+		&gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(tracing.NewGormPlugin(provider)))
+
+	type Book struct {
+		ID    int
+		Title string
+	}
+
+	// Queries outside a traced operation get no span
+	db.WithContext(context.Background()).Where("title = ?", "Dune").Find(&[]Book{})
+	assert.Empty(t, recorder.Ended())
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	db.WithContext(ctx).Where("title = ?", "Dune").Find(&[]Book{})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query := spans[0]
+	assert.Equal(t, trace.SpanKindClient, query.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, `SELECT * FROM "books" WHERE title = '?'`, attr(query, "db.statement").AsString(), "bound values are not recorded")
+	assert.Equal(t, "postgresql", attr(query, "db.system").AsString())
+	assert.Equal(t, codes.Error, query.Status().Code)
+}